@@ -0,0 +1,142 @@
+package glfw
+
+import "io"
+
+// This file describes the API that every backend must provide. It is built for all backends,
+// so a backend that drifts from the others fails to compile.
+
+// windowAPI is the set of methods a Window provides.
+type windowAPI interface {
+	MakeContextCurrent()
+	SwapBuffers()
+	Destroy()
+
+	ShouldClose() bool
+	SetShouldClose(value bool)
+	SetTitle(title string)
+	GetPos() (x, y int)
+	SetPos(xpos, ypos int)
+	GetSize() (width, height int)
+	SetSize(width, height int)
+	GetFramebufferSize() (width, height int)
+	Show()
+	Hide()
+	SetFullscreen(fullscreen bool) error
+	SetMonitor(monitor *Monitor, xpos, ypos, width, height, refreshRate int)
+	GetMonitor() *Monitor
+	GetAttrib(attrib Hint) int
+	SetKeyboardLock(enabled bool, keys ...Key) error
+	KeyboardLocked() bool
+
+	GetCursorPos() (x, y float64)
+	GetKey(key Key) Action
+	GetMouseButton(button MouseButton) Action
+	GetInputMode(mode InputMode) int
+	SetInputMode(mode InputMode, value int)
+	InputState() *InputState
+
+	SetClipboardString(str string)
+	GetClipboardString() (string, error)
+
+	SetPosCallback(cbfun PosCallback) (previous PosCallback)
+	SetSizeCallback(cbfun SizeCallback) (previous SizeCallback)
+	SetFramebufferSizeCallback(cbfun FramebufferSizeCallback) (previous FramebufferSizeCallback)
+	SetCloseCallback(cbfun CloseCallback) (previous CloseCallback)
+	SetRefreshCallback(cbfun RefreshCallback) (previous RefreshCallback)
+	SetFocusCallback(cbfun FocusCallback) (previous FocusCallback)
+	SetIconifyCallback(cbfun IconifyCallback) (previous IconifyCallback)
+	SetFullscreenCallback(cbfun FullscreenCallback) (previous FullscreenCallback)
+	SetCursorPosCallback(cbfun CursorPosCallback) (previous CursorPosCallback)
+	SetMouseMovementCallback(cbfun MouseMovementCallback) (previous MouseMovementCallback)
+	SetCursorEnterCallback(cbfun CursorEnterCallback) (previous CursorEnterCallback)
+	SetCursorModeCallback(cbfun CursorModeCallback) (previous CursorModeCallback)
+	SetMouseButtonCallback(cbfun MouseButtonCallback) (previous MouseButtonCallback)
+	SetScrollCallback(cbfun ScrollCallback) (previous ScrollCallback)
+	SetScrollModsCallback(cbfun ScrollModsCallback) (previous ScrollModsCallback)
+	SetPreciseScrollCallback(cbfun PreciseScrollCallback) (previous PreciseScrollCallback)
+	SetKeyCallback(cbfun KeyCallback) (previous KeyCallback)
+	SetCharCallback(cbfun CharCallback) (previous CharCallback)
+	SetCharModsCallback(cbfun CharModsCallback) (previous CharModsCallback)
+	SetPreeditCallback(cbfun PreeditCallback) (previous PreeditCallback)
+	SetIMECursorRect(x, y, width, height int)
+	StartTextInput(x, y, width, height int)
+	StopTextInput()
+	SetBrowserDefaultsPolicy(policy BrowserDefaultsPolicy)
+	SetDropCallback(cbfun DropCallback) (previous DropCallback)
+	SetTouchCallback(cbfun TouchCallback) (previous TouchCallback)
+	SetGestureCallback(cbfun GestureCallback) (previous GestureCallback)
+	SetGestureConfig(config GestureConfig)
+	SetPointerCallback(cbfun PointerCallback) (previous PointerCallback)
+}
+
+// monitorAPI is the set of methods a Monitor provides.
+type monitorAPI interface {
+	GetVideoMode() *VidMode
+}
+
+var (
+	_ windowAPI  = (*Window)(nil)
+	_ monitorAPI = (*Monitor)(nil)
+)
+
+// Functions.
+var _ func(width, height int, title string, monitor *Monitor, share *Window) (*Window, error) = CreateWindow
+
+var (
+	_ func(cw ContextWatcher) error            = Init
+	_ func()                                   = Terminate
+	_ func()                                   = DefaultWindowHints
+	_ func(target Hint, hint int)              = WindowHint
+	_ func(interval int)                       = SwapInterval
+	_ func()                                   = DetachCurrentContext
+	_ func() *Window                           = GetCurrentContext
+	_ func() *Monitor                          = GetPrimaryMonitor
+	_ func() ModifierKey                       = PrimaryModifier
+	_ func() bool                              = RawMouseMotionSupported
+	_ func(key Key, scancode int) string       = GetKeyName
+	_ func(key Key) int                        = GetKeyScancode
+	_ func()                                   = PollEvents
+	_ func()                                   = WaitEvents
+	_ func()                                   = PostEmptyEvent
+	_ func(name string) (io.ReadCloser, error) = Open
+	_ func(f func())                           = Main
+	_ func(f func())                           = Do
+)
+
+// Constants.
+var (
+	_ = [...]Hint{
+		ClientAPI,
+		AlphaBits, DepthBits, StencilBits, Samples, Resizable,
+		Focused, Iconified,
+		PremultipliedAlpha, PreserveDrawingBuffer, PreferLowPowerToHighPerformance, FailIfMajorPerformanceCaveat,
+	}
+	_ = [...]int{NoAPI}
+
+	_ = [...]Key{
+		KeyUnknown, KeySpace, KeyApostrophe, KeyComma, KeyMinus, KeyPeriod, KeySlash,
+		Key0, Key1, Key2, Key3, Key4, Key5, Key6, Key7, Key8, Key9,
+		KeySemicolon, KeyEqual,
+		KeyA, KeyB, KeyC, KeyD, KeyE, KeyF, KeyG, KeyH, KeyI, KeyJ, KeyK, KeyL, KeyM,
+		KeyN, KeyO, KeyP, KeyQ, KeyR, KeyS, KeyT, KeyU, KeyV, KeyW, KeyX, KeyY, KeyZ,
+		KeyLeftBracket, KeyBackslash, KeyRightBracket, KeyGraveAccent, KeyWorld1, KeyWorld2,
+		KeyEscape, KeyEnter, KeyTab, KeyBackspace, KeyInsert, KeyDelete,
+		KeyRight, KeyLeft, KeyDown, KeyUp, KeyPageUp, KeyPageDown, KeyHome, KeyEnd,
+		KeyCapsLock, KeyScrollLock, KeyNumLock, KeyPrintScreen, KeyPause,
+		KeyF1, KeyF2, KeyF3, KeyF4, KeyF5, KeyF6, KeyF7, KeyF8, KeyF9, KeyF10, KeyF11, KeyF12, KeyF13,
+		KeyF14, KeyF15, KeyF16, KeyF17, KeyF18, KeyF19, KeyF20, KeyF21, KeyF22, KeyF23, KeyF24, KeyF25,
+		KeyKP0, KeyKP1, KeyKP2, KeyKP3, KeyKP4, KeyKP5, KeyKP6, KeyKP7, KeyKP8, KeyKP9,
+		KeyKPDecimal, KeyKPDivide, KeyKPMultiply, KeyKPSubtract, KeyKPAdd, KeyKPEnter, KeyKPEqual,
+		KeyLeftShift, KeyLeftControl, KeyLeftAlt, KeyLeftSuper,
+		KeyRightShift, KeyRightControl, KeyRightAlt, KeyRightSuper, KeyMenu,
+	}
+	_ = [...]MouseButton{
+		MouseButton1, MouseButton2, MouseButton3, MouseButton4,
+		MouseButton5, MouseButton6, MouseButton7, MouseButton8, MouseButtonLast,
+		MouseButtonLeft, MouseButtonRight, MouseButtonMiddle,
+	}
+	_ = [...]Action{Release, Press, Repeat}
+	_ = [...]InputMode{CursorMode, StickyKeysMode, StickyMouseButtonsMode, LockKeyMods, TouchMouseEmulationMode, RawMouseMotion}
+	_ = [...]int{CursorNormal, CursorHidden, CursorDisabled}
+	_ = [...]ModifierKey{ModShift, ModControl, ModAlt, ModSuper, ModCapsLock, ModNumLock}
+)
@@ -3,12 +3,12 @@
 package glfw
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"runtime"
+	"strings"
 
 	"github.com/gopherjs/gopherjs/js"
 	"honnef.co/go/js/dom"
@@ -18,13 +18,16 @@ var document = dom.GetWindow().Document().(dom.HTMLDocument)
 
 var contextWatcher ContextWatcher
 
+// backend identifies this backend in input recordings, since key values differ between backends.
+const backend = "browser"
+
 func Init(cw ContextWatcher) error {
 	contextWatcher = cw
+	loadKeyboardLayout()
 	return nil
 }
 
-func Terminate() error {
-	return nil
+func Terminate() {
 }
 
 func CreateWindow(_, _ int, title string, monitor *Monitor, share *Window) (*Window, error) {
@@ -74,72 +77,93 @@ func CreateWindow(_, _ int, title string, monitor *Monitor, share *Window) (*Win
 	attrs.PreferLowPowerToHighPerformance = (hints[PreferLowPowerToHighPerformance] > 0)
 	attrs.FailIfMajorPerformanceCaveat = (hints[FailIfMajorPerformanceCaveat] > 0)
 
-	// Create GL context.
-	context, err := newContext(canvas.Underlying(), attrs)
-	if err != nil {
-		return nil, err
+	// Create GL context, unless no client API was requested.
+	var context *js.Object
+	if hints[ClientAPI] != NoAPI {
+		var err error
+		context, err = newContext(canvas.Underlying(), attrs)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	w := &Window{
-		canvas:  canvas,
-		context: context,
+		canvas:   canvas,
+		context:  context,
+		defaults: DefaultBrowserDefaultsPolicy(),
 	}
+	w.textInput = newTextInput(w)
+	w.textInput.focus()
 
-	if w.canvas.Underlying().Get("requestPointerLock") == js.Undefined ||
-		document.Underlying().Get("exitPointerLock") == js.Undefined {
-
-		w.missing.pointerLock = true
-	}
-	if w.canvas.Underlying().Get("webkitRequestFullscreen") == js.Undefined ||
-		document.Underlying().Get("webkitExitFullscreen") == js.Undefined {
-
-		w.missing.fullscreen = true
-	}
+	w.initPointerLock()
+	w.initFullscreen()
 
 	if monitor != nil {
-		if w.missing.fullscreen {
-			log.Println("warning: Fullscreen API unsupported")
-		} else {
-			w.requestFullscreen = true
-		}
+		w.SetFullscreen(true)
 	}
 
 	dom.GetWindow().AddEventListener("focus", false, func(dom.Event) {
-		if w.focusCallback != nil {
-			w.focusCallback(w, true)
-		}
+		w.queueEvent(func() { w.emitFocus(true) })
 	})
 	dom.GetWindow().AddEventListener("blur", false, func(dom.Event) {
-		if w.focusCallback != nil {
-			w.focusCallback(w, false)
-		}
+		w.queueEvent(func() { w.emitFocus(false) })
 	})
 
 	dom.GetWindow().AddEventListener("resize", false, func(event dom.Event) {
-		// HACK: Go fullscreen?
-		width := dom.GetWindow().InnerWidth()
-		height := dom.GetWindow().InnerHeight()
+		w.resize()
+	})
+	w.watchDevicePixelRatio()
+
+	if w.context != nil {
+		w.canvas.AddEventListener("webglcontextlost", false, func(event dom.Event) {
+			// Preventing the default action lets the browser restore the context.
+			event.PreventDefault()
+		})
+		w.canvas.AddEventListener("webglcontextrestored", false, func(dom.Event) {
+			w.queueEvent(w.emitRefresh)
+		})
+	}
 
-		devicePixelRatio := js.Global.Get("devicePixelRatio").Float()
-		w.canvas.Width = int(float64(width)*devicePixelRatio + 0.5)   // Nearest non-negative int.
-		w.canvas.Height = int(float64(height)*devicePixelRatio + 0.5) // Nearest non-negative int.
-		w.canvas.Style().SetProperty("width", fmt.Sprintf("%vpx", width), "")
-		w.canvas.Style().SetProperty("height", fmt.Sprintf("%vpx", height), "")
+	// Scrolling any element of the page may move the canvas. Scroll events don't bubble,
+	// so they're listened to during the capture phase. Other moves are caught by SwapBuffers.
+	w.pos[0], w.pos[1] = w.GetPos()
+	dom.GetWindow().AddEventListener("scroll", true, func(dom.Event) {
+		w.updatePos()
+	})
 
-		if w.framebufferSizeCallback != nil {
-			// TODO: Callbacks may be blocking so they need to happen asyncronously. However,
-			//       GLFW API promises the callbacks will occur from one thread (i.e., sequentially), so may want to do that.
-			go w.framebufferSizeCallback(w, w.canvas.Width, w.canvas.Height)
+	// The page is hidden when its tab is in the background, or the browser window is minimized.
+	// That's the closest browsers come to iconifying a window.
+	w.iconified = document.Underlying().Get("hidden").Bool()
+	document.AddEventListener("visibilitychange", false, func(dom.Event) {
+		iconified := document.Underlying().Get("hidden").Bool()
+		if iconified == w.iconified {
+			return
 		}
-		if w.sizeCallback != nil {
-			go w.sizeCallback(w, int(w.canvas.GetBoundingClientRect().Width), int(w.canvas.GetBoundingClientRect().Height))
+		w.iconified = iconified
+		w.queueEvent(func() { w.emitIconify(iconified) })
+		if !iconified {
+			// Browsers may discard the contents of the canvas of hidden pages.
+			w.queueEvent(w.emitRefresh)
 		}
 	})
 
+	w.canvas.AddEventListener("mouseenter", false, func(dom.Event) {
+		w.queueEvent(func() { w.emitCursorEnter(true) })
+	})
+	w.canvas.AddEventListener("mouseleave", false, func(dom.Event) {
+		w.queueEvent(func() { w.emitCursorEnter(false) })
+	})
+
 	document.AddEventListener("keydown", false, func(event dom.Event) {
 		w.goFullscreenIfRequested()
+		w.lockPointerIfRequested()
 
 		ke := event.(*dom.KeyboardEvent)
+		if isComposing(ke) {
+			// The key belongs to the input method; preventing its default action would cancel the composition.
+			w.textInput.keyEvent = false
+			return
+		}
 
 		action := Press
 		if ke.Repeat {
@@ -147,77 +171,101 @@ func CreateWindow(_, _ int, title string, monitor *Monitor, share *Window) (*Win
 		}
 
 		key := toKey(ke)
+		w.setKey(key, Press) // GetKey reports repeats as presses, as in GLFW.
 
-		// Extend slice if needed.
-		neededSize := int(key) + 1
-		if neededSize > len(w.keys) {
-			w.keys = append(w.keys, make([]Action, neededSize-len(w.keys))...)
-		}
-		w.keys[key] = action
+		mods := w.toModifierKey(ke)
+		learnKeyLabel(ke.Get("code").String(), ke.Key, mods)
+		w.textInput.mods = mods
+		w.textInput.keyEvent = true
 
-		if w.keyCallback != nil {
-			mods := toModifierKey(ke)
+		scancode := toScancode(ke)
+		w.queueEvent(func() { w.emitKey(key, scancode, action, mods) })
 
-			go w.keyCallback(w, key, -1, action, mods)
+		if w.textInput.wantsKey(ke) {
+			// Let the character be typed into the text input, which delivers it as a char event.
+			return
+		}
+		if w.capturesKey(key, mods) {
+			ke.PreventDefault()
 		}
-
-		ke.PreventDefault()
 	})
 	document.AddEventListener("keyup", false, func(event dom.Event) {
 		w.goFullscreenIfRequested()
+		w.lockPointerIfRequested()
 
 		ke := event.(*dom.KeyboardEvent)
+		if isComposing(ke) {
+			return
+		}
 
 		key := toKey(ke)
+		w.setKey(key, Release)
 
-		// Extend slice if needed.
-		neededSize := int(key) + 1
-		if neededSize > len(w.keys) {
-			w.keys = append(w.keys, make([]Action, neededSize-len(w.keys))...)
-		}
-		w.keys[key] = Release
-
-		if w.keyCallback != nil {
-			mods := toModifierKey(ke)
+		mods := w.toModifierKey(ke)
+		scancode := toScancode(ke)
+		w.queueEvent(func() { w.emitKey(key, scancode, Release, mods) })
 
-			go w.keyCallback(w, key, -1, Release, mods)
+		if w.capturesKey(key, mods) {
+			ke.PreventDefault()
 		}
-
-		ke.PreventDefault()
 	})
 
 	document.AddEventListener("mousedown", false, func(event dom.Event) {
 		w.goFullscreenIfRequested()
+		w.lockPointerIfRequested()
 
 		me := event.(*dom.MouseEvent)
-		if !(me.Button >= 0 && me.Button <= 2) {
+		if !(me.Button >= 0 && me.Button <= int(MouseButtonLast)) {
 			return
 		}
 
-		w.mouseButton[me.Button] = Press
-		if w.mouseButtonCallback != nil {
-			go w.mouseButtonCallback(w, MouseButton(me.Button), Press, 0)
+		button, mods := MouseButton(me.Button), w.toModifierKey(me)
+		w.setMouseButton(button, Press)
+		w.queueEvent(func() { w.emitMouseButton(button, Press, mods) })
+		if w.targeted(me) {
+			// Clicks elsewhere in the page are meant for what they target, which keeps focus.
+			w.textInput.focusIfRequested()
 		}
+		if w.missing.pointerEvents {
+			if e, ok := w.mousePointer.button(button, Press); ok {
+				w.queueEvent(func() { w.emitPointer(e) })
+			}
+		}
+
+		if w.defaults.Mouse.captures(w.targeted(me)) {
+			// Preventing the default action keeps focus from moving, but not from being lost.
+			w.textInput.focus()
 
-		me.PreventDefault()
+			// Also keeps the back and forward buttons from navigating away.
+			me.PreventDefault()
+		}
 	})
 	document.AddEventListener("mouseup", false, func(event dom.Event) {
 		w.goFullscreenIfRequested()
+		w.lockPointerIfRequested()
 
 		me := event.(*dom.MouseEvent)
-		if !(me.Button >= 0 && me.Button <= 2) {
+		if !(me.Button >= 0 && me.Button <= int(MouseButtonLast)) {
 			return
 		}
 
-		w.mouseButton[me.Button] = Release
-		if w.mouseButtonCallback != nil {
-			go w.mouseButtonCallback(w, MouseButton(me.Button), Release, 0)
+		button, mods := MouseButton(me.Button), w.toModifierKey(me)
+		w.setMouseButton(button, Release)
+		w.queueEvent(func() { w.emitMouseButton(button, Release, mods) })
+		if w.missing.pointerEvents {
+			if e, ok := w.mousePointer.button(button, Release); ok {
+				w.queueEvent(func() { w.emitPointer(e) })
+			}
 		}
 
-		me.PreventDefault()
+		if w.defaults.Mouse.captures(w.targeted(me)) {
+			me.PreventDefault()
+		}
 	})
 	document.AddEventListener("contextmenu", false, func(event dom.Event) {
-		event.PreventDefault()
+		if w.defaults.ContextMenu.captures(w.targeted(event)) {
+			event.PreventDefault()
+		}
 	})
 
 	document.AddEventListener("mousemove", false, func(event dom.Event) {
@@ -232,131 +280,289 @@ func CreateWindow(_, _ int, title string, monitor *Monitor, share *Window) (*Win
 			movementY = float64(me.ClientY) - w.cursorPos[1]
 		}
 
-		w.cursorPos[0], w.cursorPos[1] = float64(me.ClientX), float64(me.ClientY)
-		if w.cursorPosCallback != nil {
-			go w.cursorPosCallback(w, w.cursorPos[0], w.cursorPos[1])
-		}
-		if w.mouseMovementCallback != nil {
-			go w.mouseMovementCallback(w, w.cursorPos[0], w.cursorPos[1], movementX, movementY)
+		x, y := float64(me.ClientX), float64(me.ClientY)
+		w.cursorPos[0], w.cursorPos[1] = x, y
+		w.queueEvent(func() {
+			w.emitCursorPos(x, y)
+			w.emitMouseMovement(x, y, movementX, movementY)
+		})
+		if w.missing.pointerEvents {
+			e := w.mousePointer.move(x, y)
+			w.queueEvent(func() { w.emitPointer(e) })
 		}
 
-		me.PreventDefault()
+		if w.defaults.Mouse.captures(w.targeted(me)) {
+			me.PreventDefault()
+		}
 	})
-	document.AddEventListener("wheel", false, func(event dom.Event) {
+	addActiveEventListener("wheel", func(event dom.Event) {
 		we := event.(*dom.WheelEvent)
 
-		var multiplier float64
+		// Offsets are positive when scrolling up or left, unlike wheel deltas.
+		var unitsPerDelta float64
 		switch we.DeltaMode {
 		case dom.DeltaPixel:
-			multiplier = 0.1
+			unitsPerDelta = 1.0 / scrollPixelsPerUnit
 		case dom.DeltaLine:
-			multiplier = 1
-		default:
-			log.Println("unsupported WheelEvent.DeltaMode:", we.DeltaMode)
-			multiplier = 1
+			unitsPerDelta = 1.0 / scrollLinesPerUnit
+		case dom.DeltaPage:
+			unitsPerDelta = 1.0 / scrollPagesPerUnit
 		}
+		xoff, yoff := -we.DeltaX*unitsPerDelta, -we.DeltaY*unitsPerDelta
 
-		if w.scrollCallback != nil {
-			go w.scrollCallback(w, -we.DeltaX*multiplier, -we.DeltaY*multiplier)
-		}
+		mods, precise := w.toModifierKey(we), isPreciseScroll(we)
+		w.queueEvent(func() {
+			w.emitScroll(xoff, yoff)
+			w.emitScrollMods(xoff, yoff, mods)
+			w.emitPreciseScroll(xoff*scrollPixelsPerUnit, yoff*scrollPixelsPerUnit, precise)
+		})
 
-		we.PreventDefault()
+		if w.defaults.Mouse.captures(w.targeted(we)) {
+			we.PreventDefault()
+		}
 	})
 
-	// Hacky mouse-emulation-via-touch.
-	touchHandler := func(event dom.Event) {
-		w.goFullscreenIfRequested()
+	// Pointer and touch input. Pointer Events are preferred where available.
+	if js.Global.Get("PointerEvent") != js.Undefined {
+		// Keep the browser from panning and zooming, since that would cancel our touches.
+		w.canvas.Style().SetProperty("touch-action", "none", "")
+
+		pointerHandler := func(phase TouchPhase) func(dom.Event) {
+			return func(event dom.Event) {
+				pe := event.(*dom.PointerEvent)
+
+				// Deliver the samples that were coalesced into this event, if the browser provides them.
+				samples := []*js.Object{pe.Object}
+				if phase == TouchMoved && pe.Get("getCoalescedEvents") != js.Undefined {
+					if coalesced := pe.Call("getCoalescedEvents"); coalesced.Length() > 0 {
+						samples = samples[:0]
+						for i := 0; i < coalesced.Length(); i++ {
+							samples = append(samples, coalesced.Index(i))
+						}
+					}
+				}
+				for _, s := range samples {
+					e := toPointerEvent(s, phase)
+					w.queueEvent(func() { w.emitPointer(e) })
+				}
 
-		te := event.(*dom.TouchEvent)
+				if pe.Get("pointerType").String() != "touch" {
+					// Mouse input is handled by the mouse event handlers.
+					return
+				}
+				if phase == TouchBegan || phase == TouchEnded {
+					w.goFullscreenIfRequested()
+					w.lockPointerIfRequested()
+				}
+				if phase == TouchEnded && w.targeted(pe) {
+					w.textInput.focusIfRequested()
+				}
 
-		touches := te.Get("touches")
-		if touches.Length() > 0 {
-			t := touches.Index(0)
+				w.touch(pe.Get("pointerId").Int(), phase, pe.Get("clientX").Float(), pe.Get("clientY").Float())
 
-			if w.touches != nil && w.touches.Length() > 0 { // This event is a movement only if we previously had > 0 touch points.
-				if w.mouseMovementCallback != nil {
-					go w.mouseMovementCallback(w, t.Get("clientX").Float(), t.Get("clientY").Float(), t.Get("clientX").Float()-w.cursorPos[0], t.Get("clientY").Float()-w.cursorPos[1])
+				if w.defaults.Touch.captures(w.targeted(pe)) {
+					pe.PreventDefault()
 				}
 			}
+		}
+		document.AddEventListener("pointerdown", false, pointerHandler(TouchBegan))
+		document.AddEventListener("pointermove", false, pointerHandler(TouchMoved))
+		document.AddEventListener("pointerup", false, pointerHandler(TouchEnded))
+		document.AddEventListener("pointercancel", false, pointerHandler(TouchCancelled))
+	} else {
+		w.missing.pointerEvents = true
+
+		touchHandler := func(phase TouchPhase) func(dom.Event) {
+			return func(event dom.Event) {
+				if phase == TouchBegan || phase == TouchEnded {
+					w.goFullscreenIfRequested()
+					w.lockPointerIfRequested()
+				}
+				if phase == TouchEnded && w.targeted(event) {
+					w.textInput.focusIfRequested()
+				}
+
+				te := event.(*dom.TouchEvent)
+				for _, t := range te.ChangedTouches() {
+					w.touch(t.Identifier, phase, t.ClientX, t.ClientY)
+				}
 
-			w.cursorPos[0], w.cursorPos[1] = t.Get("clientX").Float(), t.Get("clientY").Float()
-			if w.cursorPosCallback != nil {
-				go w.cursorPosCallback(w, w.cursorPos[0], w.cursorPos[1])
+				if w.defaults.Touch.captures(w.targeted(te)) {
+					te.PreventDefault()
+				}
 			}
 		}
-		w.touches = touches
-
-		te.PreventDefault()
+		addActiveEventListener("touchstart", touchHandler(TouchBegan))
+		addActiveEventListener("touchmove", touchHandler(TouchMoved))
+		document.AddEventListener("touchend", false, touchHandler(TouchEnded))
+		document.AddEventListener("touchcancel", false, touchHandler(TouchCancelled))
 	}
-	document.AddEventListener("touchstart", false, touchHandler)
-	document.AddEventListener("touchmove", false, touchHandler)
-	document.AddEventListener("touchend", false, touchHandler)
 
 	document.AddEventListener("beforeunload", false, func(dom.Event) {
-		if w.closeCallback != nil {
-			w.closeCallback(w)
-		}
+		w.emitClose()
 	})
 
 	// Request first animation frame.
-	js.Global.Call("requestAnimationFrame", animationFrame)
+	requestAnimationFrame()
 
 	return w, nil
 }
 
-func SwapInterval(interval int) error {
-	// TODO: Implement.
-	return nil
+// swapInterval is the number of animation frames SwapBuffers waits for.
+// Zero means SwapBuffers only yields to the browser, without waiting for an animation frame.
+var swapInterval = 1
+
+// SwapInterval sets the number of animation frames to wait for in SwapBuffers.
+// An interval of 0 makes SwapBuffers return as soon as the browser had a chance
+// to process pending events, which is useful for benchmarking.
+func SwapInterval(interval int) {
+	if interval < 0 {
+		// There's no adaptive vsync in the browser; treat it like regular vsync.
+		interval = 1
+	}
+	if interval != swapInterval {
+		// Discard an animation frame that arrived while SwapBuffers didn't wait for one,
+		// so that the first SwapBuffers with the new interval waits for a fresh one.
+		select {
+		case <-animationFrameChan:
+		default:
+		}
+	}
+	swapInterval = interval
 }
 
 type Window struct {
 	canvas            *dom.HTMLCanvasElement
 	context           *js.Object
-	requestFullscreen bool // requestFullscreen is set to true when fullscreen should be entered as soon as possible (in a user input handler).
-	fullscreen        bool // fullscreen is true if we're currently in fullscreen mode.
+	requestFullscreen bool           // requestFullscreen is set to true when fullscreen should be entered as soon as possible (in a user input handler).
+	fullscreen        bool           // fullscreen is true if we're currently in fullscreen mode.
+	fullscreenAPI     *fullscreenAPI // Variant of the Fullscreen API the browser supports.
+	keyboardLock      keyboardLock   // Keyboard Lock API state, for SetKeyboardLock.
+	pointerLock       pointerLock    // Pointer Lock API state, for CursorDisabled.
 
 	// Unavailable browser APIs.
 	missing struct {
-		pointerLock bool // Pointer Lock API.
-		fullscreen  bool // Fullscreen API.
+		pointerLock   bool // Pointer Lock API.
+		pointerEvents bool // Pointer Events; the mouse is reported as a pointer instead.
+		fullscreen    bool // Fullscreen API.
 	}
 
-	cursorMode  int
-	cursorPos   [2]float64
-	mouseButton [3]Action
+	pos          [2]int // Last known position, to deliver position events when it changes.
+	iconified    bool   // iconified is true while the page is hidden.
+	cursorMode   int
+	cursorPos    [2]float64
+	mouseButton  [MouseButtonLast + 1]Action
+	mousePointer mousePointer // Reports the mouse as a pointer where Pointer Events are unavailable.
 
 	keys []Action
 
-	cursorPosCallback       CursorPosCallback
-	mouseMovementCallback   MouseMovementCallback
-	mouseButtonCallback     MouseButtonCallback
-	keyCallback             KeyCallback
-	scrollCallback          ScrollCallback
-	framebufferSizeCallback FramebufferSizeCallback
-	sizeCallback            SizeCallback
-	focusCallback           FocusCallback
-	closeCallback           CloseCallback
+	eventQueue []func() // Events waiting to be delivered by deliverEvents, in order.
+
+	textInput *textInput            // Receives text input, including that composed by input methods.
+	defaults  BrowserDefaultsPolicy // Which input events have their default actions prevented.
+
+	// TODO: Call dropCallback.
+	callbacks
 
-	touches *js.Object // Hacky mouse-emulation-via-touch.
+	stickyKeys         bool // StickyKeysMode input mode.
+	stickyMouseButtons bool // StickyMouseButtonsMode input mode.
+	lockKeyMods        bool // LockKeyMods input mode.
+	rawMouseMotion     bool // RawMouseMotion input mode.
+
+	// Mouse emulation via touch, enabled by TouchMouseEmulationMode.
+	touchMouseEmulation bool
+	primaryTouch        struct {
+		active bool // active is true while a touch point is emulating the mouse.
+		id     int  // id is the identifier of that touch point.
+	}
 }
 
-func (w *Window) SetPos(xpos, ypos int) {
-	fmt.Println("not implemented: SetPos:", xpos, ypos)
+// SetBrowserDefaultsPolicy sets which input events the window captures, by preventing their
+// default actions, and which are passed to the browser.
+func (w *Window) SetBrowserDefaultsPolicy(policy BrowserDefaultsPolicy) {
+	w.defaults = policy
 }
 
-func (w *Window) SetSize(width, height int) {
-	fmt.Println("not implemented: SetSize:", width, height)
+// capturesKey reports whether the default action of a key event is prevented.
+func (w *Window) capturesKey(key Key, mods ModifierKey) bool {
+	return w.defaults.Keys.captures(w.textInput.focused()) && !w.defaults.passes(key, mods)
+}
+
+// addActiveEventListener adds a listener to the document that can prevent default actions.
+// Browsers make wheel and touch listeners on the document passive by default, and ignore
+// PreventDefault in them.
+func addActiveEventListener(typ string, listener func(dom.Event)) {
+	document.Underlying().Call("addEventListener", typ, func(o *js.Object) {
+		listener(dom.WrapEvent(o))
+	}, js.M{"passive": false})
 }
 
-// goFullscreenIfRequested performs webkitRequestFullscreen if it was scheduled. It is called only from
-// user events, because that API will fail if called at any other time.
-func (w *Window) goFullscreenIfRequested() {
-	if !w.requestFullscreen {
+// queueEvent queues f, which delivers an event, to be called once the current DOM event handler
+// has returned, since callbacks may block. Queued events are delivered in order on one goroutine,
+// as GLFW delivers events sequentially, so that recordings and input states see them in order.
+func (w *Window) queueEvent(f func()) {
+	w.eventQueue = append(w.eventQueue, f)
+	if len(w.eventQueue) == 1 {
+		go w.deliverEvents()
+	}
+}
+
+// deliverEvents delivers queued events, including those queued meanwhile, until there are none left.
+func (w *Window) deliverEvents() {
+	for len(w.eventQueue) > 0 {
+		w.eventQueue[0]()
+		w.eventQueue[0] = nil
+		w.eventQueue = w.eventQueue[1:]
+	}
+}
+
+// targeted reports whether event targets the window.
+func (w *Window) targeted(event dom.Event) bool {
+	target := event.Target()
+	return target != nil && target.Underlying() == w.canvas.Underlying()
+}
+
+// touch delivers a touch event, and emulates the left mouse button and cursor with the first
+// touch point that's placed on the surface, if mouse emulation is enabled.
+func (w *Window) touch(id int, phase TouchPhase, x, y float64) {
+	w.queueEvent(func() { w.emitTouch(id, phase, x, y) })
+
+	if !w.touchMouseEmulation {
 		return
 	}
-	w.requestFullscreen = false
-	w.canvas.Underlying().Call("webkitRequestFullscreen")
-	w.fullscreen = true
+	switch {
+	case phase == TouchBegan && !w.primaryTouch.active:
+		w.primaryTouch.active, w.primaryTouch.id = true, id
+
+		w.cursorPos[0], w.cursorPos[1] = x, y
+		w.setMouseButton(MouseButtonLeft, Press)
+		w.queueEvent(func() {
+			w.emitCursorPos(x, y)
+			w.emitMouseButton(MouseButtonLeft, Press, 0)
+		})
+	case !w.primaryTouch.active || id != w.primaryTouch.id:
+		// Not the touch point that emulates the mouse.
+	case phase == TouchMoved:
+		xdelta, ydelta := x-w.cursorPos[0], y-w.cursorPos[1]
+		w.cursorPos[0], w.cursorPos[1] = x, y
+		w.queueEvent(func() {
+			w.emitCursorPos(x, y)
+			w.emitMouseMovement(x, y, xdelta, ydelta)
+		})
+	case phase == TouchEnded || phase == TouchCancelled:
+		w.primaryTouch.active = false
+
+		w.setMouseButton(MouseButtonLeft, Release)
+		w.queueEvent(func() { w.emitMouseButton(MouseButtonLeft, Release, 0) })
+	}
+}
+
+func (w *Window) SetPos(xpos, ypos int) {
+	reportError(PlatformError, "SetPos not implemented")
+}
+
+func (w *Window) SetSize(width, height int) {
+	reportError(PlatformError, "SetSize not implemented")
 }
 
 type Monitor struct{}
@@ -379,98 +585,137 @@ func GetPrimaryMonitor() *Monitor {
 	return &Monitor{}
 }
 
-func PollEvents() error {
-	return nil
+// PrimaryModifier returns the modifier key used for most shortcuts on the platform:
+// ModSuper (Cmd) on macOS and iOS, and ModControl elsewhere. The platform is detected
+// from the navigator.
+func PrimaryModifier() ModifierKey {
+	navigator := js.Global.Get("navigator")
+	platform := navigator.Get("platform").String()
+	if data := navigator.Get("userAgentData"); data != js.Undefined && data.Get("platform").String() != "" {
+		platform = data.Get("platform").String()
+	}
+	if strings.HasPrefix(platform, "Mac") || strings.HasPrefix(platform, "macOS") ||
+		platform == "iPhone" || platform == "iPad" || platform == "iPod" {
+		return ModSuper
+	}
+	return ModControl
 }
 
+func PollEvents() {
+	// Events have already been delivered by the time the browser lets us run.
+	pollGestures()
+	refreshInputStates()
+}
+
+// currentContext is the window whose context is current, or nil if there is none.
+var currentContext *Window
+
 func (w *Window) MakeContextCurrent() {
+	currentContext = w
 	contextWatcher.OnMakeCurrent(w.context)
 }
 
 func DetachCurrentContext() {
+	currentContext = nil
 	contextWatcher.OnDetach()
 }
 
+// GetCurrentContext returns the window whose context is current, or nil if there is none.
 func GetCurrentContext() *Window {
-	panic("not implemented")
-}
-
-type CursorPosCallback func(w *Window, xpos float64, ypos float64)
-
-func (w *Window) SetCursorPosCallback(cbfun CursorPosCallback) (previous CursorPosCallback) {
-	w.cursorPosCallback = cbfun
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type MouseMovementCallback func(w *Window, xpos float64, ypos float64, xdelta float64, ydelta float64)
-
-func (w *Window) SetMouseMovementCallback(cbfun MouseMovementCallback) (previous MouseMovementCallback) {
-	w.mouseMovementCallback = cbfun
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type KeyCallback func(w *Window, key Key, scancode int, action Action, mods ModifierKey)
-
-func (w *Window) SetKeyCallback(cbfun KeyCallback) (previous KeyCallback) {
-	w.keyCallback = cbfun
-
-	// TODO: Handle previous.
-	return nil
+	return currentContext
 }
 
-type CharCallback func(w *Window, char rune)
+func (w *Window) GetSize() (width, height int) {
+	// TODO: See if dpi adjustments need to be made.
+	fmt.Println("Window.GetSize:", w.canvas.GetBoundingClientRect().Width, w.canvas.GetBoundingClientRect().Height,
+		"->", int(w.canvas.GetBoundingClientRect().Width), int(w.canvas.GetBoundingClientRect().Height))
 
-func (w *Window) SetCharCallback(cbfun CharCallback) (previous CharCallback) {
-	// TODO.
-	return nil
+	return int(w.canvas.GetBoundingClientRect().Width), int(w.canvas.GetBoundingClientRect().Height)
 }
 
-type ScrollCallback func(w *Window, xoff float64, yoff float64)
-
-func (w *Window) SetScrollCallback(cbfun ScrollCallback) (previous ScrollCallback) {
-	w.scrollCallback = cbfun
-
-	// TODO: Handle previous.
-	return nil
+func (w *Window) GetFramebufferSize() (width, height int) {
+	return w.canvas.Width, w.canvas.Height
 }
 
-type MouseButtonCallback func(w *Window, button MouseButton, action Action, mods ModifierKey)
-
-func (w *Window) SetMouseButtonCallback(cbfun MouseButtonCallback) (previous MouseButtonCallback) {
-	w.mouseButtonCallback = cbfun
-
-	// TODO: Handle previous.
-	return nil
+// GetPos returns the position of the canvas, in screen coordinates. Browsers don't report
+// where the page is within the browser window, so it's relative to the top-left corner
+// of the browser window, including its toolbars, rather than to that of the page.
+func (w *Window) GetPos() (x, y int) {
+	rect := w.canvas.GetBoundingClientRect()
+	x = int(rect.Left) + dom.GetWindow().ScreenX()
+	y = int(rect.Top) + dom.GetWindow().ScreenY()
+	return x, y
 }
 
-type FramebufferSizeCallback func(w *Window, width int, height int)
-
-func (w *Window) SetFramebufferSizeCallback(cbfun FramebufferSizeCallback) (previous FramebufferSizeCallback) {
-	w.framebufferSizeCallback = cbfun
+// resize fits the canvas to the browser window, and its framebuffer to the device pixel ratio.
+func (w *Window) resize() {
+	// HACK: Go fullscreen?
+	width := dom.GetWindow().InnerWidth()
+	height := dom.GetWindow().InnerHeight()
 
-	// TODO: Handle previous.
-	return nil
+	devicePixelRatio := js.Global.Get("devicePixelRatio").Float()
+	w.canvas.Width = int(float64(width)*devicePixelRatio + 0.5)   // Nearest non-negative int.
+	w.canvas.Height = int(float64(height)*devicePixelRatio + 0.5) // Nearest non-negative int.
+	w.canvas.Style().SetProperty("width", fmt.Sprintf("%vpx", width), "")
+	w.canvas.Style().SetProperty("height", fmt.Sprintf("%vpx", height), "")
+
+	fbWidth, fbHeight := w.canvas.Width, w.canvas.Height
+	rect := w.canvas.GetBoundingClientRect()
+	sizeWidth, sizeHeight := int(rect.Width), int(rect.Height)
+	w.queueEvent(func() {
+		w.emitFramebufferSize(fbWidth, fbHeight)
+		w.emitSize(sizeWidth, sizeHeight)
+	})
+	w.updatePos()
+	w.queueEvent(w.emitRefresh)
 }
 
-func (w *Window) GetSize() (width, height int) {
-	// TODO: See if dpi adjustments need to be made.
-	fmt.Println("Window.GetSize:", w.canvas.GetBoundingClientRect().Width, w.canvas.GetBoundingClientRect().Height,
-		"->", int(w.canvas.GetBoundingClientRect().Width), int(w.canvas.GetBoundingClientRect().Height))
-
-	return int(w.canvas.GetBoundingClientRect().Width), int(w.canvas.GetBoundingClientRect().Height)
+// watchDevicePixelRatio resizes the canvas when the device pixel ratio changes, e.g., because
+// the browser window was moved to a monitor with a different one, which doesn't cause a resize event.
+func (w *Window) watchDevicePixelRatio() {
+	if js.Global.Get("matchMedia") == js.Undefined {
+		return
+	}
+	query := js.Global.Call("matchMedia", fmt.Sprintf("(resolution: %vdppx)", js.Global.Get("devicePixelRatio").Float()))
+	if query.Get("addEventListener") == js.Undefined {
+		return
+	}
+	// The query only matches the current ratio, so it's replaced by a new one after each change.
+	query.Call("addEventListener", "change", func() {
+		w.resize()
+		w.watchDevicePixelRatio()
+	}, js.M{"once": true})
 }
 
-func (w *Window) GetFramebufferSize() (width, height int) {
-	return w.canvas.Width, w.canvas.Height
+// updatePos delivers a position event if the canvas moved, e.g., because the page was scrolled,
+// its layout changed, or the browser window was moved.
+func (w *Window) updatePos() {
+	x, y := w.GetPos()
+	if x == w.pos[0] && y == w.pos[1] {
+		return
+	}
+	w.pos[0], w.pos[1] = x, y
+	w.queueEvent(func() { w.emitPos(x, y) })
 }
 
-func (w *Window) GetPos() (x, y int) {
-	// Not implemented.
-	return
+// GetAttrib returns the value of a window attribute. Focused and Iconified are supported
+// in the browser, where the window is iconified while the page is hidden, e.g., because
+// its tab is in the background.
+func (w *Window) GetAttrib(attrib Hint) int {
+	var value bool
+	switch attrib {
+	case Focused:
+		value = document.Underlying().Call("hasFocus").Bool()
+	case Iconified:
+		value = w.iconified
+	default:
+		reportError(InvalidEnum, fmt.Sprintf("invalid window attribute 0x%08X", int(attrib)))
+		return 0
+	}
+	if value {
+		return 1
+	}
+	return 0
 }
 
 func (w *Window) ShouldClose() bool {
@@ -483,59 +728,132 @@ func (w *Window) SetShouldClose(value bool) {
 	//        Perhaps https://developer.mozilla.org/en-US/docs/Web/API/Window.close is relevant.
 }
 
-func (w *Window) SwapBuffers() error {
-	<-animationFrameChan
-	js.Global.Call("requestAnimationFrame", animationFrame)
+func (w *Window) SwapBuffers() {
+	// There are no events for the browser window moving, or the page layout changing, so check every frame.
+	w.updatePos()
 
-	return nil
+	interval := swapInterval
+	if interval == 0 && w.iconified {
+		// Browsers don't run animation frames in hidden pages, so waiting for one keeps
+		// hidden pages from rendering as fast as they can when vsync is disabled.
+		interval = 1
+	}
+	if interval == 0 {
+		yieldToBrowser()
+		return
+	}
+
+	for i := 0; i < interval; i++ {
+		requestAnimationFrame()
+		<-animationFrameChan
+	}
+	requestAnimationFrame()
 }
 
-var animationFrameChan = make(chan struct{}, 1)
+var (
+	animationFrameChan      = make(chan struct{}, 1)
+	animationFrameRequested bool // animationFrameRequested is true while an animation frame request is pending.
+)
+
+// requestAnimationFrame schedules animationFrame to be called before the next repaint,
+// unless that has already been done.
+func requestAnimationFrame() {
+	if animationFrameRequested {
+		return
+	}
+	animationFrameRequested = true
+	js.Global.Call("requestAnimationFrame", animationFrame)
+}
 
 func animationFrame() {
-	animationFrameChan <- struct{}{}
+	animationFrameRequested = false
+	select {
+	case animationFrameChan <- struct{}{}:
+	default:
+		// A previous animation frame hasn't been consumed yet, e.g., because swap interval is 0.
+	}
+}
+
+var (
+	yieldChan      = make(chan struct{}, 1)
+	messageChannel *js.Object // messageChannel is used to schedule a task without the 4 ms clamping of setTimeout.
+)
+
+// yieldToBrowser returns after the browser had a chance to run its event loop,
+// without waiting for the next animation frame.
+func yieldToBrowser() {
+	resume := func() { yieldChan <- struct{}{} }
+	if messageChannel == nil && js.Global.Get("MessageChannel") != js.Undefined {
+		messageChannel = js.Global.Get("MessageChannel").New()
+		messageChannel.Get("port1").Set("onmessage", resume)
+	}
+	if messageChannel != nil {
+		messageChannel.Get("port2").Call("postMessage", nil)
+	} else {
+		js.Global.Call("setTimeout", resume, 0)
+	}
+	<-yieldChan
 }
 
 func (w *Window) GetCursorPos() (x, y float64) {
 	return w.cursorPos[0], w.cursorPos[1]
 }
 
-var keyWarnings = 10
-
 func (w *Window) GetKey(key Key) Action {
-	if key == -1 && keyWarnings > 0 {
-		// TODO: Implement all keys, get rid of this.
-		keyWarnings--
-		log.Println("GetKey: key not implemented.")
+	if key == KeyUnknown {
+		reportError(InvalidEnum, "invalid key KeyUnknown")
 		return Release
 	}
-	if int(key) >= len(w.keys) {
+	if key < 0 || int(key) >= len(w.keys) {
 		return Release
 	}
+	if w.keys[key] == stick {
+		w.keys[key] = Release
+		return Press
+	}
 	return w.keys[key]
 }
 
-func (w *Window) GetMouseButton(button MouseButton) Action {
-	if !(button >= 0 && button <= 2) {
-		panic(fmt.Errorf("button is out of range: %v", button))
+// stick is the state of a key or mouse button that was released while sticky keys or
+// mouse buttons were enabled, but not yet queried. It's reported as Press once.
+const stick Action = 3
+
+// setKey updates the state of key, following sticky keys mode.
+func (w *Window) setKey(key Key, action Action) {
+	if key < 0 {
+		return
 	}
 
-	// Hacky mouse-emulation-via-touch.
-	if w.touches != nil {
-		switch button {
-		case MouseButton1:
-			if w.touches.Length() == 1 || w.touches.Length() == 3 {
-				return Press
-			}
-		case MouseButton2:
-			if w.touches.Length() == 2 || w.touches.Length() == 3 {
-				return Press
-			}
-		}
+	// Extend slice if needed.
+	neededSize := int(key) + 1
+	if neededSize > len(w.keys) {
+		w.keys = append(w.keys, make([]Action, neededSize-len(w.keys))...)
+	}
 
+	if action == Release && w.stickyKeys && w.keys[key] == Press {
+		action = stick
+	}
+	w.keys[key] = action
+}
+
+// setMouseButton updates the state of button, following sticky mouse buttons mode.
+func (w *Window) setMouseButton(button MouseButton, action Action) {
+	if action == Release && w.stickyMouseButtons && w.mouseButton[button] == Press {
+		action = stick
+	}
+	w.mouseButton[button] = action
+}
+
+func (w *Window) GetMouseButton(button MouseButton) Action {
+	if !(button >= 0 && button <= MouseButtonLast) {
+		reportError(InvalidEnum, fmt.Sprintf("invalid mouse button %v", button))
 		return Release
 	}
 
+	if w.mouseButton[button] == stick {
+		w.mouseButton[button] = Release
+		return Press
+	}
 	return w.mouseButton[button]
 }
 
@@ -543,53 +861,119 @@ func (w *Window) GetInputMode(mode InputMode) int {
 	switch mode {
 	case CursorMode:
 		return w.cursorMode
+	case StickyKeysMode:
+		if w.stickyKeys {
+			return 1
+		}
+		return 0
+	case StickyMouseButtonsMode:
+		if w.stickyMouseButtons {
+			return 1
+		}
+		return 0
+	case LockKeyMods:
+		if w.lockKeyMods {
+			return 1
+		}
+		return 0
+	case TouchMouseEmulationMode:
+		if w.touchMouseEmulation {
+			return 1
+		}
+		return 0
+	case RawMouseMotion:
+		if w.rawMouseMotion {
+			return 1
+		}
+		return 0
 	default:
-		panic(errors.New("not implemented"))
+		reportError(InvalidEnum, fmt.Sprintf("invalid input mode 0x%08X", int(mode)))
+		return 0
 	}
 }
 
-var ErrInvalidParameter = errors.New("invalid parameter")
-var ErrInvalidValue = errors.New("invalid value")
+// ErrInvalidParameter and ErrInvalidValue were panicked with by SetInputMode.
+//
+// Deprecated: Errors are reported to the error callback as InvalidEnum and InvalidValue instead,
+// which these are now aliases of.
+var (
+	ErrInvalidParameter error = InvalidEnum
+	ErrInvalidValue     error = InvalidValue
+)
 
 func (w *Window) SetInputMode(mode InputMode, value int) {
 	switch mode {
 	case CursorMode:
-		if w.missing.pointerLock {
-			log.Println("warning: Pointer Lock API unsupported")
-			return
-		}
 		switch value {
-		case CursorNormal:
-			w.cursorMode = value
-			document.Underlying().Call("exitPointerLock")
-			w.canvas.Style().SetProperty("cursor", "initial", "")
-			return
-		case CursorHidden:
-			w.cursorMode = value
-			document.Underlying().Call("exitPointerLock")
-			w.canvas.Style().SetProperty("cursor", "none", "")
-			return
+		case CursorNormal, CursorHidden:
+			w.pointerLock.want, w.pointerLock.request = false, false
+			if w.cursorMode == CursorDisabled {
+				document.Underlying().Call("exitPointerLock")
+			}
+			w.setCursorMode(value)
 		case CursorDisabled:
-			w.cursorMode = value
-			w.canvas.Underlying().Call("requestPointerLock")
-			return
+			if w.missing.pointerLock {
+				reportError(PlatformError, "Pointer Lock API unsupported")
+				return
+			}
+			if w.pointerLock.want {
+				return
+			}
+			// The cursor mode changes once the browser has locked the pointer.
+			w.pointerLock.want = true
+			w.lockPointer(false)
 		default:
-			panic(ErrInvalidValue)
+			reportError(InvalidValue, fmt.Sprintf("invalid cursor mode 0x%08X", value))
+		}
+	case RawMouseMotion:
+		w.rawMouseMotion = value != 0
+		if w.cursorMode == CursorDisabled {
+			// Lock the pointer again, with or without unadjusted movement.
+			w.lockPointer(false)
 		}
 	case StickyKeysMode:
-		panic(errors.New("not implemented"))
+		w.stickyKeys = value != 0
+		if !w.stickyKeys {
+			// Release keys that are only held down by being sticky.
+			for key := range w.keys {
+				if w.keys[key] == stick {
+					w.keys[key] = Release
+				}
+			}
+		}
 	case StickyMouseButtonsMode:
-		panic(errors.New("not implemented"))
+		w.stickyMouseButtons = value != 0
+		if !w.stickyMouseButtons {
+			// Release mouse buttons that are only held down by being sticky.
+			for button := range w.mouseButton {
+				if w.mouseButton[button] == stick {
+					w.mouseButton[button] = Release
+				}
+			}
+		}
+	case LockKeyMods:
+		w.lockKeyMods = value != 0
+	case TouchMouseEmulationMode:
+		w.touchMouseEmulation = value != 0
 	default:
-		panic(ErrInvalidParameter)
+		reportError(InvalidEnum, fmt.Sprintf("invalid input mode 0x%08X", int(mode)))
 	}
 }
 
+// Key is a key of the keyboard, identified by its position rather than by its label, as in GLFW:
+// KeyW is the key labeled Z on an AZERTY keyboard. GetKeyName returns the label.
+//
+// Keys have the legacy keyCode values browsers generate, see
+// https://developer.mozilla.org/en-US/docs/Web/API/KeyboardEvent/keyCode. Keys that share
+// a keyCode with another key, or have none, have the values of GLFW instead.
+//
+// Before GetKeyName was added, the browser backend identified keys by their label, and keys
+// without a keyCode value had invalid negative values.
 type Key int
 
-// TODO: Keys defined as -iota-1 need to be set to a valid positive value that matches the keyCode
-//       generated by browsers. -iota-1 is used as a temporary solution to have unique but invalid values.
-//       See https://developer.mozilla.org/en-US/docs/Web/API/KeyboardEvent/keyCode.
+// KeyUnknown is reported for keys the browser doesn't identify.
+const KeyUnknown Key = -1
+
 const (
 	KeySpace        Key = 32
 	KeyApostrophe   Key = 222
@@ -639,27 +1023,27 @@ const (
 	KeyBackslash    Key = 220
 	KeyRightBracket Key = 221
 	KeyGraveAccent  Key = 192
-	KeyWorld1       Key = -iota - 1
-	KeyWorld2       Key = -iota - 1
+	KeyWorld1       Key = 161
+	KeyWorld2       Key = 162
 	KeyEscape       Key = 27
 	KeyEnter        Key = 13
 	KeyTab          Key = 9
 	KeyBackspace    Key = 8
-	KeyInsert       Key = -iota - 1
+	KeyInsert       Key = 45
 	KeyDelete       Key = 46
 	KeyRight        Key = 39
 	KeyLeft         Key = 37
 	KeyDown         Key = 40
 	KeyUp           Key = 38
-	KeyPageUp       Key = -iota - 1
-	KeyPageDown     Key = -iota - 1
-	KeyHome         Key = -iota - 1
-	KeyEnd          Key = -iota - 1
+	KeyPageUp       Key = 33
+	KeyPageDown     Key = 34
+	KeyHome         Key = 36
+	KeyEnd          Key = 35
 	KeyCapsLock     Key = 20
-	KeyScrollLock   Key = -iota - 1
-	KeyNumLock      Key = -iota - 1
-	KeyPrintScreen  Key = -iota - 1
-	KeyPause        Key = -iota - 1
+	KeyScrollLock   Key = 145
+	KeyNumLock      Key = 144
+	KeyPrintScreen  Key = 44
+	KeyPause        Key = 19
 	KeyF1           Key = 112
 	KeyF2           Key = 113
 	KeyF3           Key = 114
@@ -672,36 +1056,36 @@ const (
 	KeyF10          Key = 121
 	KeyF11          Key = 122
 	KeyF12          Key = 123
-	KeyF13          Key = -iota - 1
-	KeyF14          Key = -iota - 1
-	KeyF15          Key = -iota - 1
-	KeyF16          Key = -iota - 1
-	KeyF17          Key = -iota - 1
-	KeyF18          Key = -iota - 1
-	KeyF19          Key = -iota - 1
-	KeyF20          Key = -iota - 1
-	KeyF21          Key = -iota - 1
-	KeyF22          Key = -iota - 1
-	KeyF23          Key = -iota - 1
-	KeyF24          Key = -iota - 1
-	KeyF25          Key = -iota - 1
-	KeyKP0          Key = -iota - 1
-	KeyKP1          Key = -iota - 1
-	KeyKP2          Key = -iota - 1
-	KeyKP3          Key = -iota - 1
-	KeyKP4          Key = -iota - 1
-	KeyKP5          Key = -iota - 1
-	KeyKP6          Key = -iota - 1
-	KeyKP7          Key = -iota - 1
-	KeyKP8          Key = -iota - 1
-	KeyKP9          Key = -iota - 1
-	KeyKPDecimal    Key = -iota - 1
-	KeyKPDivide     Key = -iota - 1
-	KeyKPMultiply   Key = -iota - 1
-	KeyKPSubtract   Key = -iota - 1
-	KeyKPAdd        Key = -iota - 1
-	KeyKPEnter      Key = -iota - 1
-	KeyKPEqual      Key = -iota - 1
+	KeyF13          Key = 124
+	KeyF14          Key = 125
+	KeyF15          Key = 126
+	KeyF16          Key = 127
+	KeyF17          Key = 128
+	KeyF18          Key = 129
+	KeyF19          Key = 130
+	KeyF20          Key = 131
+	KeyF21          Key = 132
+	KeyF22          Key = 133
+	KeyF23          Key = 134
+	KeyF24          Key = 135
+	KeyF25          Key = 314
+	KeyKP0          Key = 96
+	KeyKP1          Key = 97
+	KeyKP2          Key = 98
+	KeyKP3          Key = 99
+	KeyKP4          Key = 100
+	KeyKP5          Key = 101
+	KeyKP6          Key = 102
+	KeyKP7          Key = 103
+	KeyKP8          Key = 104
+	KeyKP9          Key = 105
+	KeyKPDecimal    Key = 110
+	KeyKPDivide     Key = 111
+	KeyKPMultiply   Key = 106
+	KeyKPSubtract   Key = 109
+	KeyKPAdd        Key = 107
+	KeyKPEnter      Key = 335
+	KeyKPEqual      Key = 336
 	KeyLeftShift    Key = 340
 	KeyLeftControl  Key = 341
 	KeyLeftAlt      Key = 342
@@ -710,11 +1094,25 @@ const (
 	KeyRightControl Key = 345
 	KeyRightAlt     Key = 346
 	KeyRightSuper   Key = 93
-	KeyMenu         Key = -iota - 1
+	KeyMenu         Key = 348
 )
 
+// toScancode extracts the scancode from given KeyboardEvent, or -1 if the key is unknown.
+func toScancode(ke *dom.KeyboardEvent) int {
+	key, ok := codeKeys[ke.Get("code").String()]
+	if !ok {
+		return -1
+	}
+	return keyScancodes[key]
+}
+
 // toKey extracts Key from given KeyboardEvent.
 func toKey(ke *dom.KeyboardEvent) Key {
+	// Prefer the physical key, which doesn't depend on the keyboard layout, as in GLFW.
+	if key, ok := codeKeys[ke.Get("code").String()]; ok {
+		return key
+	}
+
 	key := Key(ke.KeyCode)
 	switch {
 	case key == 16 && ke.Location == dom.KeyLocationLeft:
@@ -733,21 +1131,72 @@ func toKey(ke *dom.KeyboardEvent) Key {
 	return key
 }
 
-// toModifierKey extracts ModifierKey from given KeyboardEvent.
-func toModifierKey(ke *dom.KeyboardEvent) ModifierKey {
+// isPreciseScroll guesses whether a WheelEvent comes from a device that scrolls smoothly,
+// such as a trackpad. Mouse wheels are reported in lines by Firefox, and with a legacy
+// wheelDelta in multiples of 120 by other browsers.
+func isPreciseScroll(we *dom.WheelEvent) bool {
+	if we.DeltaMode != dom.DeltaPixel {
+		return false
+	}
+	wheelDeltaX, wheelDeltaY := we.Get("wheelDeltaX"), we.Get("wheelDeltaY")
+	if wheelDeltaX == js.Undefined || wheelDeltaY == js.Undefined {
+		return true
+	}
+	return wheelDeltaX.Int()%120 != 0 || wheelDeltaY.Int()%120 != 0
+}
+
+// toPointerEvent converts a DOM PointerEvent to a PointerEvent with the given phase.
+func toPointerEvent(pe *js.Object, phase TouchPhase) PointerEvent {
+	var typ PointerType
+	switch pe.Get("pointerType").String() {
+	case "pen":
+		typ = PointerPen
+	case "touch":
+		typ = PointerTouch
+	default:
+		typ = PointerMouse
+	}
+	return PointerEvent{
+		ID:                 pe.Get("pointerId").Int(),
+		Type:               typ,
+		Phase:              phase,
+		X:                  pe.Get("clientX").Float(),
+		Y:                  pe.Get("clientY").Float(),
+		Pressure:           pe.Get("pressure").Float(),
+		TangentialPressure: pe.Get("tangentialPressure").Float(),
+		TiltX:              pe.Get("tiltX").Float(),
+		TiltY:              pe.Get("tiltY").Float(),
+		Twist:              pe.Get("twist").Float(),
+		Width:              pe.Get("width").Float(),
+		Height:             pe.Get("height").Float(),
+	}
+}
+
+// toModifierKey extracts ModifierKey from given KeyboardEvent, MouseEvent or WheelEvent.
+// Lock key modifiers are only included if LockKeyMods is enabled.
+func (w *Window) toModifierKey(event dom.Event) ModifierKey {
+	e := event.Underlying()
 	mods := ModifierKey(0)
-	if ke.ShiftKey {
+	if e.Get("shiftKey").Bool() {
 		mods += ModShift
 	}
-	if ke.CtrlKey {
+	if e.Get("ctrlKey").Bool() {
 		mods += ModControl
 	}
-	if ke.AltKey {
+	if e.Get("altKey").Bool() {
 		mods += ModAlt
 	}
-	if ke.MetaKey {
+	if e.Get("metaKey").Bool() {
 		mods += ModSuper
 	}
+	if w.lockKeyMods && e.Get("getModifierState") != js.Undefined {
+		if e.Call("getModifierState", "CapsLock").Bool() {
+			mods += ModCapsLock
+		}
+		if e.Call("getModifierState", "NumLock").Bool() {
+			mods += ModNumLock
+		}
+	}
 	return mods
 }
 
@@ -757,6 +1206,13 @@ const (
 	MouseButton1 MouseButton = 0
 	MouseButton2 MouseButton = 2 // Web MouseEvent has middle and right mouse buttons in reverse order.
 	MouseButton3 MouseButton = 1 // Web MouseEvent has middle and right mouse buttons in reverse order.
+	MouseButton4 MouseButton = 3 // Back.
+	MouseButton5 MouseButton = 4 // Forward.
+	MouseButton6 MouseButton = 5
+	MouseButton7 MouseButton = 6
+	MouseButton8 MouseButton = 7
+
+	MouseButtonLast = MouseButton8
 
 	MouseButtonLeft   = MouseButton1
 	MouseButtonRight  = MouseButton2
@@ -777,6 +1233,16 @@ const (
 	CursorMode InputMode = iota
 	StickyKeysMode
 	StickyMouseButtonsMode
+	LockKeyMods
+
+	// TouchMouseEmulationMode, when set to a non-zero value, makes the first touch point
+	// that's placed on the surface also act as the cursor and left mouse button.
+	// It's disabled by default.
+	TouchMouseEmulationMode
+
+	// RawMouseMotion, when set to a non-zero value, locks the pointer with unadjusted movement
+	// while the cursor is disabled, so that movement isn't accelerated. It's disabled by default.
+	RawMouseMotion
 )
 
 const (
@@ -792,6 +1258,8 @@ const (
 	ModControl
 	ModAlt
 	ModSuper
+	ModCapsLock
+	ModNumLock
 )
 
 // Open opens a named asset. It's the caller's responsibility to close it when done.
@@ -812,6 +1280,8 @@ func WaitEvents() {
 	// TODO.
 
 	runtime.Gosched()
+	pollGestures()
+	refreshInputStates()
 }
 
 func PostEmptyEvent() {
@@ -819,7 +1289,32 @@ func PostEmptyEvent() {
 }
 
 func DefaultWindowHints() {
-	// TODO: Implement.
+	hints = make(map[Hint]int)
+}
+
+// StartTextInput shows the soft keyboard on devices that have one, such as phones, and places
+// it, and input method windows, next to the given area of the window, in window coordinates
+// relative to its top-left corner, as for cursor positions.
+// Text typed with it is delivered to CharCallback and CharModsCallback; deleting text is
+// reported as presses of KeyBackspace, and line breaks as presses of KeyEnter.
+//
+// Browsers only show the soft keyboard in response to user input, so if StartTextInput isn't
+// called from a callback of a user input event, the keyboard shows at the next tap or click
+// on the window.
+func (w *Window) StartTextInput(x, y, width, height int) {
+	w.textInput.start(x, y, width, height)
+}
+
+// StopTextInput hides the soft keyboard shown by StartTextInput.
+func (w *Window) StopTextInput() {
+	w.textInput.stop()
+}
+
+// SetIMECursorRect sets the area of the window where text is being edited, in window coordinates
+// relative to its top-left corner, as for cursor positions, so that input method windows can be
+// placed next to it.
+func (w *Window) SetIMECursorRect(x, y, width, height int) {
+	w.textInput.setCursorRect(x, y, width, height)
 }
 
 func (w *Window) SetClipboardString(str string) {
@@ -827,7 +1322,7 @@ func (w *Window) SetClipboardString(str string) {
 }
 func (w *Window) GetClipboardString() (string, error) {
 	// TODO: Implement.
-	return "", errors.New("GetClipboardString not implemented")
+	return "", reportError(PlatformError, "GetClipboardString not implemented")
 }
 
 func (w *Window) SetTitle(title string) {
@@ -844,91 +1339,12 @@ func (w *Window) Hide() {
 
 func (w *Window) Destroy() {
 	document.Body().RemoveChild(w.canvas)
+	document.Body().RemoveChild(w.textInput.textarea)
+	delete(inputTrackers, w)
+	delete(gestureRecognizers, w)
+	w.unlockKeyboard()
 	if w.fullscreen {
-		if w.missing.fullscreen {
-			log.Println("warning: Fullscreen API unsupported")
-		} else {
-			document.Underlying().Call("webkitExitFullscreen")
-			w.fullscreen = false
-		}
+		document.Underlying().Call(w.fullscreenAPI.exit)
+		w.fullscreen = false
 	}
 }
-
-type CloseCallback func(w *Window)
-
-func (w *Window) SetCloseCallback(cbfun CloseCallback) (previous CloseCallback) {
-	previous = w.closeCallback
-	w.closeCallback = cbfun
-	return previous
-}
-
-type RefreshCallback func(w *Window)
-
-func (w *Window) SetRefreshCallback(cbfun RefreshCallback) (previous RefreshCallback) {
-	// TODO: Implement.
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type SizeCallback func(w *Window, width int, height int)
-
-func (w *Window) SetSizeCallback(cbfun SizeCallback) (previous SizeCallback) {
-	w.sizeCallback = cbfun
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type CursorEnterCallback func(w *Window, entered bool)
-
-func (w *Window) SetCursorEnterCallback(cbfun CursorEnterCallback) (previous CursorEnterCallback) {
-	// TODO: Implement.
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type CharModsCallback func(w *Window, char rune, mods ModifierKey)
-
-func (w *Window) SetCharModsCallback(cbfun CharModsCallback) (previous CharModsCallback) {
-	// TODO: Implement.
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type PosCallback func(w *Window, xpos int, ypos int)
-
-func (w *Window) SetPosCallback(cbfun PosCallback) (previous PosCallback) {
-	// TODO: Implement.
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type FocusCallback func(w *Window, focused bool)
-
-func (w *Window) SetFocusCallback(cbfun FocusCallback) (previous FocusCallback) {
-	previous = w.focusCallback
-	w.focusCallback = cbfun
-	return previous
-}
-
-type IconifyCallback func(w *Window, iconified bool)
-
-func (w *Window) SetIconifyCallback(cbfun IconifyCallback) (previous IconifyCallback) {
-	// TODO: Implement.
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type DropCallback func(w *Window, names []string)
-
-func (w *Window) SetDropCallback(cbfun DropCallback) (previous DropCallback) {
-	// TODO: Implement. Can use HTML5 file drag and drop API?
-
-	// TODO: Handle previous.
-	return nil
-}
@@ -0,0 +1,74 @@
+package glfw
+
+// Capture chooses when the browser backend prevents the default actions of input events,
+// i.e., keeps the browser from acting on them, e.g., scrolling the page or going back.
+// Events are delivered to callbacks either way.
+type Capture int
+
+const (
+	// CaptureWhenFocused prevents default actions of key events while the window has keyboard
+	// focus, and of pointer events that target the window. Focus is given to the window when
+	// it's created and when it's clicked, and lost when something else in the page is clicked.
+	CaptureWhenFocused Capture = iota
+
+	CaptureAlways // Always prevent default actions, even for events meant for other parts of the page.
+	CaptureNever  // Never prevent default actions.
+)
+
+// BrowserDefaultsPolicy chooses which input events the browser backend captures, by preventing
+// their default actions, and which are passed to the browser.
+type BrowserDefaultsPolicy struct {
+	Keys        Capture // Key events.
+	Mouse       Capture // Mouse button, movement and wheel events.
+	Touch       Capture // Touch events.
+	ContextMenu Capture // The context menu shown by a right click.
+
+	// PassKeys are chords passed to the browser even when key events are captured,
+	// so that browser shortcuts keep working. Lock key modifiers are ignored.
+	PassKeys []Chord
+}
+
+// DefaultBrowserDefaultsPolicy returns the policy windows start with. It captures events
+// while the window has focus, except for the shortcuts of the browser to reload the page,
+// focus the address bar, zoom, and switch fullscreen and developer tools.
+func DefaultBrowserDefaultsPolicy() BrowserDefaultsPolicy {
+	primary := PrimaryModifier()
+	return BrowserDefaultsPolicy{
+		PassKeys: []Chord{
+			// Reload.
+			{KeyF5, 0}, {KeyF5, ModShift}, {KeyF5, primary}, {KeyR, primary}, {KeyR, primary | ModShift},
+			// Focus the address bar.
+			{KeyL, primary},
+			// Zoom in, out, and reset zoom.
+			{KeyEqual, primary}, {KeyEqual, primary | ModShift}, {KeyKPAdd, primary},
+			{KeyMinus, primary}, {KeyKPSubtract, primary},
+			{Key0, primary}, {KeyKP0, primary},
+			// Fullscreen and developer tools.
+			{KeyF11, 0}, {KeyF12, 0},
+		},
+	}
+}
+
+// passes reports whether the key event with key and mods is passed to the browser.
+func (p *BrowserDefaultsPolicy) passes(key Key, mods ModifierKey) bool {
+	chord := Chord{Key: key, Mods: mods &^ (ModCapsLock | ModNumLock)}
+	for _, c := range p.PassKeys {
+		if c == chord {
+			return true
+		}
+	}
+	return false
+}
+
+// captures reports whether an event is captured under c. focused reports whether the window
+// has keyboard focus, for key events, or whether the event targets the window, for others.
+func (c Capture) captures(focused bool) bool {
+	switch c {
+	case CaptureAlways:
+		return true
+	case CaptureNever:
+		return false
+	default:
+		return focused
+	}
+}
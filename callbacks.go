@@ -0,0 +1,484 @@
+package glfw
+
+// callbacks holds the callbacks set on a Window. It is embedded by the Window of each backend,
+// which delivers events to them via the emit methods below.
+type callbacks struct {
+	posCallback             PosCallback
+	sizeCallback            SizeCallback
+	framebufferSizeCallback FramebufferSizeCallback
+	closeCallback           CloseCallback
+	refreshCallback         RefreshCallback
+	focusCallback           FocusCallback
+	iconifyCallback         IconifyCallback
+	fullscreenCallback      FullscreenCallback
+	cursorPosCallback       CursorPosCallback
+	mouseMovementCallback   MouseMovementCallback
+	cursorEnterCallback     CursorEnterCallback
+	cursorModeCallback      CursorModeCallback
+	mouseButtonCallback     MouseButtonCallback
+	scrollCallback          ScrollCallback
+	scrollModsCallback      ScrollModsCallback
+	preciseScrollCallback   PreciseScrollCallback
+	keyCallback             KeyCallback
+	charCallback            CharCallback
+	charModsCallback        CharModsCallback
+	preeditCallback         PreeditCallback
+	dropCallback            DropCallback
+	touchCallback           TouchCallback
+	gestureCallback         GestureCallback
+	pointerCallback         PointerCallback
+
+	gestures  *gestureRecognizer // Created when gestures are first asked for.
+	synthetic bool               // synthetic is true while events made up from other events are delivered.
+
+	// listeners are called for each event before the callbacks above. They allow features
+	// such as recording to observe events without taking over the callbacks of the user.
+	listeners []*callbacks
+}
+
+func (w *Window) addListener(l *callbacks) {
+	w.listeners = append(w.listeners, l)
+}
+
+func (w *Window) removeListener(l *callbacks) {
+	for i := range w.listeners {
+		if w.listeners[i] == l {
+			w.listeners = append(w.listeners[:i:i], w.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// The emit methods deliver an event to the listeners of w, followed by the callback set on w.
+
+func (w *Window) emitPos(xpos int, ypos int) {
+	for _, l := range w.listeners {
+		if l.posCallback != nil {
+			l.posCallback(w, xpos, ypos)
+		}
+	}
+	if w.posCallback != nil {
+		w.posCallback(w, xpos, ypos)
+	}
+}
+
+func (w *Window) emitSize(width int, height int) {
+	for _, l := range w.listeners {
+		if l.sizeCallback != nil {
+			l.sizeCallback(w, width, height)
+		}
+	}
+	if w.sizeCallback != nil {
+		w.sizeCallback(w, width, height)
+	}
+}
+
+func (w *Window) emitFramebufferSize(width int, height int) {
+	for _, l := range w.listeners {
+		if l.framebufferSizeCallback != nil {
+			l.framebufferSizeCallback(w, width, height)
+		}
+	}
+	if w.framebufferSizeCallback != nil {
+		w.framebufferSizeCallback(w, width, height)
+	}
+}
+
+func (w *Window) emitClose() {
+	for _, l := range w.listeners {
+		if l.closeCallback != nil {
+			l.closeCallback(w)
+		}
+	}
+	if w.closeCallback != nil {
+		w.closeCallback(w)
+	}
+}
+
+func (w *Window) emitRefresh() {
+	for _, l := range w.listeners {
+		if l.refreshCallback != nil {
+			l.refreshCallback(w)
+		}
+	}
+	if w.refreshCallback != nil {
+		w.refreshCallback(w)
+	}
+}
+
+func (w *Window) emitFocus(focused bool) {
+	for _, l := range w.listeners {
+		if l.focusCallback != nil {
+			l.focusCallback(w, focused)
+		}
+	}
+	if w.focusCallback != nil {
+		w.focusCallback(w, focused)
+	}
+}
+
+func (w *Window) emitIconify(iconified bool) {
+	for _, l := range w.listeners {
+		if l.iconifyCallback != nil {
+			l.iconifyCallback(w, iconified)
+		}
+	}
+	if w.iconifyCallback != nil {
+		w.iconifyCallback(w, iconified)
+	}
+}
+
+func (w *Window) emitFullscreen(fullscreen bool) {
+	for _, l := range w.listeners {
+		if l.fullscreenCallback != nil {
+			l.fullscreenCallback(w, fullscreen)
+		}
+	}
+	if w.fullscreenCallback != nil {
+		w.fullscreenCallback(w, fullscreen)
+	}
+}
+
+func (w *Window) emitCursorPos(xpos float64, ypos float64) {
+	for _, l := range w.listeners {
+		if l.cursorPosCallback != nil {
+			l.cursorPosCallback(w, xpos, ypos)
+		}
+	}
+	if w.cursorPosCallback != nil {
+		w.cursorPosCallback(w, xpos, ypos)
+	}
+}
+
+func (w *Window) emitMouseMovement(xpos float64, ypos float64, xdelta float64, ydelta float64) {
+	for _, l := range w.listeners {
+		if l.mouseMovementCallback != nil {
+			l.mouseMovementCallback(w, xpos, ypos, xdelta, ydelta)
+		}
+	}
+	if w.mouseMovementCallback != nil {
+		w.mouseMovementCallback(w, xpos, ypos, xdelta, ydelta)
+	}
+}
+
+func (w *Window) emitCursorEnter(entered bool) {
+	for _, l := range w.listeners {
+		if l.cursorEnterCallback != nil {
+			l.cursorEnterCallback(w, entered)
+		}
+	}
+	if w.cursorEnterCallback != nil {
+		w.cursorEnterCallback(w, entered)
+	}
+}
+
+func (w *Window) emitCursorMode(mode int) {
+	for _, l := range w.listeners {
+		if l.cursorModeCallback != nil {
+			l.cursorModeCallback(w, mode)
+		}
+	}
+	if w.cursorModeCallback != nil {
+		w.cursorModeCallback(w, mode)
+	}
+}
+
+func (w *Window) emitMouseButton(button MouseButton, action Action, mods ModifierKey) {
+	for _, l := range w.listeners {
+		if l.mouseButtonCallback != nil {
+			l.mouseButtonCallback(w, button, action, mods)
+		}
+	}
+	if w.mouseButtonCallback != nil {
+		w.mouseButtonCallback(w, button, action, mods)
+	}
+}
+
+func (w *Window) emitScroll(xoff float64, yoff float64) {
+	for _, l := range w.listeners {
+		if l.scrollCallback != nil {
+			l.scrollCallback(w, xoff, yoff)
+		}
+	}
+	if w.scrollCallback != nil {
+		w.scrollCallback(w, xoff, yoff)
+	}
+}
+
+func (w *Window) emitScrollMods(xoff float64, yoff float64, mods ModifierKey) {
+	for _, l := range w.listeners {
+		if l.scrollModsCallback != nil {
+			l.scrollModsCallback(w, xoff, yoff, mods)
+		}
+	}
+	if w.scrollModsCallback != nil {
+		w.scrollModsCallback(w, xoff, yoff, mods)
+	}
+}
+
+func (w *Window) emitKey(key Key, scancode int, action Action, mods ModifierKey) {
+	for _, l := range w.listeners {
+		if l.keyCallback != nil {
+			l.keyCallback(w, key, scancode, action, mods)
+		}
+	}
+	if w.keyCallback != nil {
+		w.keyCallback(w, key, scancode, action, mods)
+	}
+}
+
+func (w *Window) emitChar(char rune) {
+	for _, l := range w.listeners {
+		if l.charCallback != nil {
+			l.charCallback(w, char)
+		}
+	}
+	if w.charCallback != nil {
+		w.charCallback(w, char)
+	}
+}
+
+func (w *Window) emitCharMods(char rune, mods ModifierKey) {
+	for _, l := range w.listeners {
+		if l.charModsCallback != nil {
+			l.charModsCallback(w, char, mods)
+		}
+	}
+	if w.charModsCallback != nil {
+		w.charModsCallback(w, char, mods)
+	}
+}
+
+func (w *Window) emitPreedit(preedit string, cursor int, committed string) {
+	for _, l := range w.listeners {
+		if l.preeditCallback != nil {
+			l.preeditCallback(w, preedit, cursor, committed)
+		}
+	}
+	if w.preeditCallback != nil {
+		w.preeditCallback(w, preedit, cursor, committed)
+	}
+}
+
+func (w *Window) emitDrop(names []string) {
+	for _, l := range w.listeners {
+		if l.dropCallback != nil {
+			l.dropCallback(w, names)
+		}
+	}
+	if w.dropCallback != nil {
+		w.dropCallback(w, names)
+	}
+}
+
+func (w *Window) emitTouch(id int, phase TouchPhase, x float64, y float64) {
+	for _, l := range w.listeners {
+		if l.touchCallback != nil {
+			l.touchCallback(w, id, phase, x, y)
+		}
+	}
+	if w.touchCallback != nil {
+		w.touchCallback(w, id, phase, x, y)
+	}
+}
+
+type PosCallback func(w *Window, xpos int, ypos int)
+
+func (w *Window) SetPosCallback(cbfun PosCallback) (previous PosCallback) {
+	previous = w.posCallback
+	w.posCallback = cbfun
+	return previous
+}
+
+type SizeCallback func(w *Window, width int, height int)
+
+func (w *Window) SetSizeCallback(cbfun SizeCallback) (previous SizeCallback) {
+	previous = w.sizeCallback
+	w.sizeCallback = cbfun
+	return previous
+}
+
+type FramebufferSizeCallback func(w *Window, width int, height int)
+
+func (w *Window) SetFramebufferSizeCallback(cbfun FramebufferSizeCallback) (previous FramebufferSizeCallback) {
+	previous = w.framebufferSizeCallback
+	w.framebufferSizeCallback = cbfun
+	return previous
+}
+
+type CloseCallback func(w *Window)
+
+func (w *Window) SetCloseCallback(cbfun CloseCallback) (previous CloseCallback) {
+	previous = w.closeCallback
+	w.closeCallback = cbfun
+	return previous
+}
+
+type RefreshCallback func(w *Window)
+
+func (w *Window) SetRefreshCallback(cbfun RefreshCallback) (previous RefreshCallback) {
+	previous = w.refreshCallback
+	w.refreshCallback = cbfun
+	return previous
+}
+
+type FocusCallback func(w *Window, focused bool)
+
+func (w *Window) SetFocusCallback(cbfun FocusCallback) (previous FocusCallback) {
+	previous = w.focusCallback
+	w.focusCallback = cbfun
+	return previous
+}
+
+type IconifyCallback func(w *Window, iconified bool)
+
+func (w *Window) SetIconifyCallback(cbfun IconifyCallback) (previous IconifyCallback) {
+	previous = w.iconifyCallback
+	w.iconifyCallback = cbfun
+	return previous
+}
+
+// FullscreenCallback is called when the window enters or leaves fullscreen. In the browser,
+// that includes the user leaving fullscreen, e.g., by pressing Esc.
+type FullscreenCallback func(w *Window, fullscreen bool)
+
+func (w *Window) SetFullscreenCallback(cbfun FullscreenCallback) (previous FullscreenCallback) {
+	previous = w.fullscreenCallback
+	w.fullscreenCallback = cbfun
+	return previous
+}
+
+type CursorPosCallback func(w *Window, xpos float64, ypos float64)
+
+func (w *Window) SetCursorPosCallback(cbfun CursorPosCallback) (previous CursorPosCallback) {
+	previous = w.cursorPosCallback
+	w.cursorPosCallback = cbfun
+	return previous
+}
+
+type MouseMovementCallback func(w *Window, xpos float64, ypos float64, xdelta float64, ydelta float64)
+
+func (w *Window) SetMouseMovementCallback(cbfun MouseMovementCallback) (previous MouseMovementCallback) {
+	previous = w.mouseMovementCallback
+	w.mouseMovementCallback = cbfun
+	return previous
+}
+
+type CursorEnterCallback func(w *Window, entered bool)
+
+func (w *Window) SetCursorEnterCallback(cbfun CursorEnterCallback) (previous CursorEnterCallback) {
+	previous = w.cursorEnterCallback
+	w.cursorEnterCallback = cbfun
+	return previous
+}
+
+// CursorModeCallback is called when the cursor mode changes, to CursorNormal, CursorHidden or
+// CursorDisabled. In the browser, CursorDisabled takes effect once the browser has locked
+// the pointer, which may only happen at the next user input, and ends when the user
+// releases the lock, e.g., by pressing Esc. The lock is then requested again at the next
+// user input, unless the cursor mode is set to CursorNormal or CursorHidden.
+type CursorModeCallback func(w *Window, mode int)
+
+func (w *Window) SetCursorModeCallback(cbfun CursorModeCallback) (previous CursorModeCallback) {
+	previous = w.cursorModeCallback
+	w.cursorModeCallback = cbfun
+	return previous
+}
+
+type MouseButtonCallback func(w *Window, button MouseButton, action Action, mods ModifierKey)
+
+func (w *Window) SetMouseButtonCallback(cbfun MouseButtonCallback) (previous MouseButtonCallback) {
+	previous = w.mouseButtonCallback
+	w.mouseButtonCallback = cbfun
+	return previous
+}
+
+type ScrollCallback func(w *Window, xoff float64, yoff float64)
+
+func (w *Window) SetScrollCallback(cbfun ScrollCallback) (previous ScrollCallback) {
+	previous = w.scrollCallback
+	w.scrollCallback = cbfun
+	return previous
+}
+
+// ScrollModsCallback is called with the same offsets as ScrollCallback, along with
+// the modifier keys held while scrolling. It's called after ScrollCallback.
+//
+// In the browser, trackpad pinches are reported as scrolling with ModControl held.
+type ScrollModsCallback func(w *Window, xoff float64, yoff float64, mods ModifierKey)
+
+func (w *Window) SetScrollModsCallback(cbfun ScrollModsCallback) (previous ScrollModsCallback) {
+	previous = w.scrollModsCallback
+	w.scrollModsCallback = cbfun
+	return previous
+}
+
+type KeyCallback func(w *Window, key Key, scancode int, action Action, mods ModifierKey)
+
+func (w *Window) SetKeyCallback(cbfun KeyCallback) (previous KeyCallback) {
+	previous = w.keyCallback
+	w.keyCallback = cbfun
+	return previous
+}
+
+type CharCallback func(w *Window, char rune)
+
+func (w *Window) SetCharCallback(cbfun CharCallback) (previous CharCallback) {
+	previous = w.charCallback
+	w.charCallback = cbfun
+	return previous
+}
+
+type CharModsCallback func(w *Window, char rune, mods ModifierKey)
+
+func (w *Window) SetCharModsCallback(cbfun CharModsCallback) (previous CharModsCallback) {
+	previous = w.charModsCallback
+	w.charModsCallback = cbfun
+	return previous
+}
+
+// PreeditCallback is called while an input method (IME) composes text. preedit is the text
+// being composed, and cursor is the position of the cursor within it, in runes. When the
+// composition ends, it's called with an empty preedit and the committed text, which is also
+// delivered to CharCallback and CharModsCallback, one character at a time.
+//
+// Composition is only reported in the browser. GLFW doesn't report it on desktop,
+// where input methods show their own composition window and commit text as characters.
+type PreeditCallback func(w *Window, preedit string, cursor int, committed string)
+
+func (w *Window) SetPreeditCallback(cbfun PreeditCallback) (previous PreeditCallback) {
+	previous = w.preeditCallback
+	w.preeditCallback = cbfun
+	return previous
+}
+
+type DropCallback func(w *Window, names []string)
+
+func (w *Window) SetDropCallback(cbfun DropCallback) (previous DropCallback) {
+	previous = w.dropCallback
+	w.dropCallback = cbfun
+	return previous
+}
+
+// TouchPhase is the phase of a touch point.
+type TouchPhase int
+
+const (
+	TouchBegan     TouchPhase = iota // The touch point was placed on the surface.
+	TouchMoved                       // The touch point moved.
+	TouchEnded                       // The touch point was removed from the surface.
+	TouchCancelled                   // The touch point was disrupted, e.g., because the browser took over the gesture.
+)
+
+// TouchCallback is called when a touch point changes. id identifies the touch point
+// for as long as it's on the surface; x and y are its position in screen coordinates
+// relative to the window, like those of CursorPosCallback.
+//
+// Touch input is only available in the browser, as GLFW doesn't support it on desktop.
+type TouchCallback func(w *Window, id int, phase TouchPhase, x float64, y float64)
+
+func (w *Window) SetTouchCallback(cbfun TouchCallback) (previous TouchCallback) {
+	previous = w.touchCallback
+	w.touchCallback = cbfun
+	return previous
+}
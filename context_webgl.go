@@ -2,15 +2,11 @@
 
 package glfw
 
-import (
-	"errors"
-
-	"github.com/gopherjs/gopherjs/js"
-)
+import "github.com/gopherjs/gopherjs/js"
 
 func newContext(canvas *js.Object, ca *contextAttributes) (context *js.Object, err error) {
 	if js.Global.Get("WebGLRenderingContext") == js.Undefined {
-		return nil, errors.New("Your browser doesn't appear to support WebGL.")
+		return nil, reportError(APIUnavailable, "Your browser doesn't appear to support WebGL.")
 	}
 
 	attrs := map[string]bool{
@@ -29,7 +25,7 @@ func newContext(canvas *js.Object, ca *contextAttributes) (context *js.Object, e
 	} else if gl := canvas.Call("getContext", "experimental-webgl", attrs); gl != nil {
 		return gl, nil
 	} else {
-		return nil, errors.New("Creating a WebGL context has failed.")
+		return nil, reportError(APIUnavailable, "Creating a WebGL context has failed.")
 	}
 }
 
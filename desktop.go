@@ -4,6 +4,7 @@ package glfw
 
 import (
 	"io"
+	"math"
 	"os"
 	"runtime"
 
@@ -16,197 +17,508 @@ func init() {
 
 var contextWatcher ContextWatcher
 
+// backend identifies this backend in input recordings, since key values differ between backends.
+const backend = "desktop"
+
+// windows maps the underlying glfw windows to the Windows that wrap them.
+var windows = make(map[*glfw.Window]*Window)
+
 // Init initializes the library.
 //
 // A valid ContextWatcher must be provided. It gets notified when context becomes current or detached.
 // It should be provided by the GL bindings you are using, so you can do glfw.Init(gl.ContextWatcher).
 func Init(cw ContextWatcher) error {
 	contextWatcher = cw
-	return glfw.Init()
+	if err := glfw.Init(); err != nil {
+		return convertError(err)
+	}
+
+	initializedMu.Lock()
+	initialized = true
+	initializedMu.Unlock()
+	return nil
 }
 
 func Terminate() {
+	initializedMu.Lock()
+	initialized = false
+	initializedMu.Unlock()
+
+	defer recoverError()
 	glfw.Terminate()
 }
 
 func CreateWindow(width, height int, title string, monitor *Monitor, share *Window) (*Window, error) {
 	var m *glfw.Monitor
 	if monitor != nil {
-		m = monitor.Monitor
+		m = monitor.monitor
 	}
 	var s *glfw.Window
 	if share != nil {
-		s = share.Window
+		s = share.window
 	}
 
 	w, err := glfw.CreateWindow(width, height, title, m, s)
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
-	window := &Window{Window: w}
+	window := &Window{window: w}
+	window.windowed.xpos, window.windowed.ypos = w.GetPos()
+	window.windowed.width, window.windowed.height = width, height
+	window.cursorPos[0], window.cursorPos[1] = w.GetCursorPos()
+	window.mousePointer.pos = window.cursorPos
+	window.setGLFWCallbacks()
+	windows[w] = window
 
-	return window, err
+	return window, nil
 }
 
+// SwapInterval sets the number of screen updates to wait for before swapping the buffers
+// of a window and returning from SwapBuffers. An interval of 0 disables vsync.
 func SwapInterval(interval int) {
+	defer recoverError()
 	glfw.SwapInterval(interval)
 }
 
 func (w *Window) MakeContextCurrent() {
-	w.Window.MakeContextCurrent()
+	defer recoverError()
+	w.window.MakeContextCurrent()
 	// In reality, context is available on each platform via GetGLXContext, GetWGLContext, GetNSGLContext, etc.
 	// Pretend it is not available and pass nil, since it's not actually needed at this time.
 	contextWatcher.OnMakeCurrent(nil)
 }
 
 func DetachCurrentContext() {
+	defer recoverError()
 	glfw.DetachCurrentContext()
 	contextWatcher.OnDetach()
 }
 
-type Window struct {
-	*glfw.Window
+// GetCurrentContext returns the window whose context is current, or nil if there is none.
+func GetCurrentContext() *Window {
+	return windows[glfw.GetCurrentContext()]
 }
 
-type Monitor struct {
-	*glfw.Monitor
+// convertError converts an error returned by glfw to an *Error, and reports it.
+func convertError(err error) error {
+	e, ok := err.(*glfw.Error)
+	if !ok {
+		return err
+	}
+	return reportError(ErrorCode(e.Code), e.Desc)
 }
 
-func GetPrimaryMonitor() *Monitor {
-	m := glfw.GetPrimaryMonitor()
-	return &Monitor{Monitor: m}
+// recoverError recovers from a panic caused by a glfw error, and reports the error instead.
+// It must be deferred directly.
+func recoverError() {
+	if r := recover(); r != nil {
+		recoveredError(r)
+	}
 }
 
-func PollEvents() {
-	glfw.PollEvents()
+// recoverErrorTo is like recoverError, but also stores the error in *err.
+// It must be deferred directly.
+func recoverErrorTo(err *error) {
+	if r := recover(); r != nil {
+		*err = recoveredError(r)
+	}
 }
 
-type CursorPosCallback func(w *Window, xpos float64, ypos float64)
-
-func (w *Window) SetCursorPosCallback(cbfun CursorPosCallback) (previous CursorPosCallback) {
-	wrappedCbfun := func(_ *glfw.Window, xpos float64, ypos float64) {
-		cbfun(w, xpos, ypos)
+// recoveredError reports the glfw error that caused panic r, and returns it. Other panics are resumed.
+func recoveredError(r interface{}) error {
+	e, ok := r.(*glfw.Error)
+	if !ok {
+		panic(r)
 	}
+	return reportError(ErrorCode(e.Code), e.Desc)
+}
 
-	p := w.Window.SetCursorPosCallback(wrappedCbfun)
-	_ = p
+type Window struct {
+	window *glfw.Window
 
-	// TODO: Handle previous.
-	return nil
-}
+	callbacks
 
-type MouseMovementCallback func(w *Window, xpos float64, ypos float64, xdelta float64, ydelta float64)
+	cursorPos [2]float64 // Last known cursor position, used to compute mouse movement deltas.
 
-var lastMousePos [2]float64 // HACK.
+	touchMouseEmulation int // Value of TouchMouseEmulationMode, which has no effect on desktop.
 
-// TODO: For now, this overrides SetCursorPosCallback; should support both.
-func (w *Window) SetMouseMovementCallback(cbfun MouseMovementCallback) (previous MouseMovementCallback) {
-	lastMousePos[0], lastMousePos[1] = w.Window.GetCursorPos()
-	wrappedCbfun := func(_ *glfw.Window, xpos float64, ypos float64) {
-		xdelta, ydelta := xpos-lastMousePos[0], ypos-lastMousePos[1]
-		lastMousePos[0], lastMousePos[1] = xpos, ypos
-		cbfun(w, xpos, ypos, xdelta, ydelta)
-	}
+	mousePointer mousePointer // GLFW doesn't report pointers, so the mouse is reported as one.
 
-	p := w.Window.SetCursorPosCallback(wrappedCbfun)
-	_ = p
+	lockMods ModifierKey            // Lock key modifiers of the last key or mouse button event.
+	heldMods [glfw.KeyLast + 1]bool // Modifier keys held down, by key, as reported by key events.
 
-	// TODO: Handle previous.
-	return nil
+	// Position and size of the window before SetFullscreen made it fullscreen, to restore them.
+	windowed struct {
+		xpos, ypos, width, height int
+	}
 }
 
-type KeyCallback func(w *Window, key Key, scancode int, action Action, mods ModifierKey)
+// modifierKeys returns the modifier keys currently held, for events GLFW doesn't report them with.
+// They're tracked from key events rather than queried, since querying a key consumes its sticky
+// press in StickyKeysMode. Lock key modifiers are those of the last key or mouse button event.
+func (w *Window) modifierKeys() ModifierKey {
+	mods := w.lockMods
+	held := func(left, right Key) bool {
+		return w.heldMods[left] || w.heldMods[right]
+	}
+	if held(KeyLeftShift, KeyRightShift) {
+		mods |= ModShift
+	}
+	if held(KeyLeftControl, KeyRightControl) {
+		mods |= ModControl
+	}
+	if held(KeyLeftAlt, KeyRightAlt) {
+		mods |= ModAlt
+	}
+	if held(KeyLeftSuper, KeyRightSuper) {
+		mods |= ModSuper
+	}
+	return mods
+}
+
+// queueEvent calls f, which delivers an event. Events are delivered as they occur on desktop,
+// by the callbacks of glfw, so there's no need to queue them as in the browser.
+func (w *Window) queueEvent(f func()) {
+	f()
+}
+
+// setGLFWCallbacks sets callbacks on the underlying glfw window that deliver events to w.
+func (w *Window) setGLFWCallbacks() {
+	w.window.SetPosCallback(func(_ *glfw.Window, xpos int, ypos int) {
+		w.emitPos(xpos, ypos)
+	})
+	w.window.SetSizeCallback(func(_ *glfw.Window, width int, height int) {
+		w.emitSize(width, height)
+	})
+	w.window.SetFramebufferSizeCallback(func(_ *glfw.Window, width int, height int) {
+		w.emitFramebufferSize(width, height)
+	})
+	w.window.SetCloseCallback(func(_ *glfw.Window) {
+		w.emitClose()
+	})
+	w.window.SetRefreshCallback(func(_ *glfw.Window) {
+		w.emitRefresh()
+	})
+	w.window.SetFocusCallback(func(_ *glfw.Window, focused bool) {
+		w.emitFocus(focused)
+	})
+	w.window.SetIconifyCallback(func(_ *glfw.Window, iconified bool) {
+		w.emitIconify(iconified)
+	})
+	w.window.SetCursorPosCallback(func(_ *glfw.Window, xpos float64, ypos float64) {
+		xdelta, ydelta := xpos-w.cursorPos[0], ypos-w.cursorPos[1]
+		w.cursorPos[0], w.cursorPos[1] = xpos, ypos
+
+		w.emitCursorPos(xpos, ypos)
+		w.emitMouseMovement(xpos, ypos, xdelta, ydelta)
+		w.emitPointer(w.mousePointer.move(xpos, ypos))
+	})
+	w.window.SetCursorEnterCallback(func(_ *glfw.Window, entered bool) {
+		w.emitCursorEnter(entered)
+	})
+	w.window.SetMouseButtonCallback(func(_ *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		w.lockMods = ModifierKey(mods) & (ModCapsLock | ModNumLock)
+		w.emitMouseButton(MouseButton(button), Action(action), ModifierKey(mods))
+		if e, ok := w.mousePointer.button(MouseButton(button), Action(action)); ok {
+			w.emitPointer(e)
+		}
+	})
+	w.window.SetScrollCallback(func(_ *glfw.Window, xoff float64, yoff float64) {
+		w.emitScroll(xoff, yoff)
+		w.emitScrollMods(xoff, yoff, w.modifierKeys())
+		precise := xoff != math.Trunc(xoff) || yoff != math.Trunc(yoff)
+		w.emitPreciseScroll(xoff*scrollPixelsPerUnit, yoff*scrollPixelsPerUnit, precise)
+	})
+	w.window.SetKeyCallback(func(_ *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		w.lockMods = ModifierKey(mods) & (ModCapsLock | ModNumLock)
+		if isModifierKey(Key(key)) {
+			w.heldMods[key] = action != glfw.Release
+		}
+		w.emitKey(Key(key), scancode, Action(action), ModifierKey(mods))
+	})
+	w.window.SetCharCallback(func(_ *glfw.Window, char rune) {
+		w.emitChar(char)
+	})
+	w.window.SetCharModsCallback(func(_ *glfw.Window, char rune, mods glfw.ModifierKey) {
+		w.emitCharMods(char, ModifierKey(mods))
+	})
+	w.window.SetDropCallback(func(_ *glfw.Window, names []string) {
+		w.emitDrop(names)
+	})
+}
+
+func (w *Window) SwapBuffers() {
+	defer recoverError()
+	w.window.SwapBuffers()
+}
+
+func (w *Window) Destroy() {
+	defer recoverError()
+	delete(windows, w.window)
+	delete(inputTrackers, w)
+	delete(gestureRecognizers, w)
+	w.window.Destroy()
+}
+
+func (w *Window) ShouldClose() bool {
+	defer recoverError()
+	return w.window.ShouldClose()
+}
+
+func (w *Window) SetShouldClose(value bool) {
+	defer recoverError()
+	w.window.SetShouldClose(value)
+}
+
+func (w *Window) SetTitle(title string) {
+	defer recoverError()
+	w.window.SetTitle(title)
+}
+
+func (w *Window) GetPos() (x, y int) {
+	defer recoverError()
+	return w.window.GetPos()
+}
+
+func (w *Window) SetPos(xpos, ypos int) {
+	defer recoverError()
+	w.window.SetPos(xpos, ypos)
+}
+
+func (w *Window) GetSize() (width, height int) {
+	defer recoverError()
+	return w.window.GetSize()
+}
+
+func (w *Window) SetSize(width, height int) {
+	defer recoverError()
+	w.window.SetSize(width, height)
+}
+
+func (w *Window) GetFramebufferSize() (width, height int) {
+	defer recoverError()
+	return w.window.GetFramebufferSize()
+}
+
+func (w *Window) Show() {
+	defer recoverError()
+	w.window.Show()
+}
+
+func (w *Window) Hide() {
+	defer recoverError()
+	w.window.Hide()
+}
 
-func (w *Window) SetKeyCallback(cbfun KeyCallback) (previous KeyCallback) {
-	wrappedCbfun := func(_ *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-		cbfun(w, Key(key), scancode, Action(action), ModifierKey(mods))
+// SetFullscreen makes the window fullscreen on the primary monitor, using its current video mode,
+// or restores the position and size the window had before.
+func (w *Window) SetFullscreen(fullscreen bool) (err error) {
+	defer recoverErrorTo(&err)
+	if fullscreen == (w.window.GetMonitor() != nil) {
+		return nil
+	}
+	if !fullscreen {
+		w.setMonitor(nil, w.windowed.xpos, w.windowed.ypos, w.windowed.width, w.windowed.height, glfw.DontCare)
+		return nil
 	}
+	m := glfw.GetPrimaryMonitor()
+	if m == nil {
+		return reportError(PlatformError, "no monitor to make the window fullscreen on")
+	}
+	w.windowed.xpos, w.windowed.ypos = w.window.GetPos()
+	w.windowed.width, w.windowed.height = w.window.GetSize()
+	vm := m.GetVideoMode()
+	if vm == nil {
+		return reportError(PlatformError, "no video mode to make the window fullscreen with")
+	}
+	w.setMonitor(m, 0, 0, vm.Width, vm.Height, vm.RefreshRate)
+	return nil
+}
 
-	p := w.Window.SetKeyCallback(wrappedCbfun)
-	_ = p
+// SetMonitor makes the window fullscreen on monitor, or windowed if monitor is nil,
+// with the given position, size and refresh rate.
+func (w *Window) SetMonitor(monitor *Monitor, xpos, ypos, width, height, refreshRate int) {
+	defer recoverError()
+	var m *glfw.Monitor
+	if monitor != nil {
+		m = monitor.monitor
+	}
+	w.setMonitor(m, xpos, ypos, width, height, refreshRate)
+}
 
-	// TODO: Handle previous.
-	return nil
+// setMonitor calls SetMonitor, and delivers a fullscreen event if the window entered or left fullscreen,
+// since GLFW doesn't report that.
+func (w *Window) setMonitor(m *glfw.Monitor, xpos, ypos, width, height, refreshRate int) {
+	wasFullscreen := w.window.GetMonitor() != nil
+	w.window.SetMonitor(m, xpos, ypos, width, height, refreshRate)
+	if fullscreen := m != nil; fullscreen != wasFullscreen {
+		w.emitFullscreen(fullscreen)
+	}
 }
 
-type CharCallback func(w *Window, char rune)
+// SetKeyboardLock chooses whether keys the browser normally acts on are delivered to the window
+// while it's fullscreen. It has no effect on desktop, where the window gets all keys.
+func (w *Window) SetKeyboardLock(enabled bool, keys ...Key) error { return nil }
 
-func (w *Window) SetCharCallback(cbfun CharCallback) (previous CharCallback) {
-	wrappedCbfun := func(_ *glfw.Window, char rune) {
-		cbfun(w, char)
+// KeyboardLocked reports whether keys are locked by SetKeyboardLock. It's always false on desktop.
+func (w *Window) KeyboardLocked() bool { return false }
+
+// GetMonitor returns the monitor of the window if it's fullscreen, or nil otherwise.
+func (w *Window) GetMonitor() *Monitor {
+	defer recoverError()
+	m := w.window.GetMonitor()
+	if m == nil {
+		return nil
 	}
+	return &Monitor{monitor: m}
+}
 
-	p := w.Window.SetCharCallback(wrappedCbfun)
-	_ = p
+// GetAttrib returns the value of a window attribute.
+func (w *Window) GetAttrib(attrib Hint) int {
+	defer recoverError()
+	return w.window.GetAttrib(glfw.Hint(attrib))
+}
 
-	// TODO: Handle previous.
-	return nil
+func (w *Window) GetCursorPos() (x, y float64) {
+	defer recoverError()
+	return w.window.GetCursorPos()
 }
 
-type ScrollCallback func(w *Window, xoff float64, yoff float64)
+// SetBrowserDefaultsPolicy sets which input events the browser backend captures.
+// It has no effect on desktop.
+func (w *Window) SetBrowserDefaultsPolicy(policy BrowserDefaultsPolicy) {}
 
-func (w *Window) SetScrollCallback(cbfun ScrollCallback) (previous ScrollCallback) {
-	wrappedCbfun := func(_ *glfw.Window, xoff float64, yoff float64) {
-		cbfun(w, xoff, yoff)
-	}
+// StartTextInput shows the soft keyboard on devices that have one, and places input method windows
+// next to the given area of the window, in window coordinates relative to its top-left corner, as for
+// cursor positions. It has no effect on desktop, where text input is always enabled.
+func (w *Window) StartTextInput(x, y, width, height int) {}
 
-	p := w.Window.SetScrollCallback(wrappedCbfun)
-	_ = p
+// StopTextInput hides the soft keyboard shown by StartTextInput. It has no effect on desktop.
+func (w *Window) StopTextInput() {}
 
-	// TODO: Handle previous.
-	return nil
+// SetIMECursorRect sets the area of the window where text is being edited, in window coordinates
+// relative to its top-left corner, as for cursor positions, so that input method windows can be
+// placed next to it. It has no effect on desktop, where GLFW 3.3 leaves the placement of input
+// method windows to the system.
+func (w *Window) SetIMECursorRect(x, y, width, height int) {}
+
+func (w *Window) SetClipboardString(str string) {
+	defer recoverError()
+	w.window.SetClipboardString(str)
+}
+
+func (w *Window) GetClipboardString() (str string, err error) {
+	defer recoverErrorTo(&err)
+	return w.window.GetClipboardString(), nil
 }
 
-type MouseButtonCallback func(w *Window, button MouseButton, action Action, mods ModifierKey)
+type Monitor struct {
+	monitor *glfw.Monitor
+}
 
-func (w *Window) SetMouseButtonCallback(cbfun MouseButtonCallback) (previous MouseButtonCallback) {
-	wrappedCbfun := func(_ *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
-		cbfun(w, MouseButton(button), Action(action), ModifierKey(mods))
+// GetVideoMode returns the current video mode of the monitor, or nil if it can't be
+// determined, e.g., because the monitor was disconnected.
+func (m *Monitor) GetVideoMode() *VidMode {
+	defer recoverError()
+	vm := m.monitor.GetVideoMode()
+	if vm == nil {
+		reportError(PlatformError, "failed to get the video mode of the monitor")
+		return nil
+	}
+	return &VidMode{
+		Width:       vm.Width,
+		Height:      vm.Height,
+		RedBits:     vm.RedBits,
+		GreenBits:   vm.GreenBits,
+		BlueBits:    vm.BlueBits,
+		RefreshRate: vm.RefreshRate,
 	}
+}
 
-	p := w.Window.SetMouseButtonCallback(wrappedCbfun)
-	_ = p
+// GetPrimaryMonitor returns the primary monitor, or nil if no monitor was found.
+func GetPrimaryMonitor() *Monitor {
+	defer recoverError()
+	m := glfw.GetPrimaryMonitor()
+	if m == nil {
+		return nil
+	}
+	return &Monitor{monitor: m}
+}
 
-	// TODO: Handle previous.
-	return nil
+// GetKeyName returns the label of a printable key in the current keyboard layout,
+// e.g., "z" for KeyW on an AZERTY keyboard. If key is KeyUnknown, the key is identified
+// by scancode instead. It returns "" for keys that aren't printable.
+func GetKeyName(key Key, scancode int) string {
+	defer recoverError()
+	return glfw.GetKeyName(glfw.Key(key), scancode)
 }
 
-type FramebufferSizeCallback func(w *Window, width int, height int)
+// GetKeyScancode returns the platform-specific scancode of key, or -1 if it has none.
+func GetKeyScancode(key Key) int {
+	defer recoverError()
+	return glfw.GetKeyScancode(glfw.Key(key))
+}
 
-func (w *Window) SetFramebufferSizeCallback(cbfun FramebufferSizeCallback) (previous FramebufferSizeCallback) {
-	wrappedCbfun := func(_ *glfw.Window, width int, height int) {
-		cbfun(w, width, height)
+// PrimaryModifier returns the modifier key used for most shortcuts on the platform:
+// ModSuper (Cmd) on macOS, and ModControl elsewhere.
+func PrimaryModifier() ModifierKey {
+	if runtime.GOOS == "darwin" {
+		return ModSuper
 	}
+	return ModControl
+}
 
-	p := w.Window.SetFramebufferSizeCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
+func PollEvents() {
+	defer recoverError()
+	glfw.PollEvents()
+	pollGestures()
+	refreshInputStates()
 }
 
 func (w *Window) GetKey(key Key) Action {
-	a := w.Window.GetKey(glfw.Key(key))
+	defer recoverError()
+	a := w.window.GetKey(glfw.Key(key))
 	return Action(a)
 }
 
 func (w *Window) GetMouseButton(button MouseButton) Action {
-	a := w.Window.GetMouseButton(glfw.MouseButton(button))
+	defer recoverError()
+	a := w.window.GetMouseButton(glfw.MouseButton(button))
 	return Action(a)
 }
 
 func (w *Window) GetInputMode(mode InputMode) int {
-	return w.Window.GetInputMode(glfw.InputMode(mode))
+	if mode == TouchMouseEmulationMode {
+		return w.touchMouseEmulation
+	}
+	defer recoverError()
+	return w.window.GetInputMode(glfw.InputMode(mode))
 }
 
 func (w *Window) SetInputMode(mode InputMode, value int) {
-	w.Window.SetInputMode(glfw.InputMode(mode), value)
+	if mode == TouchMouseEmulationMode {
+		w.touchMouseEmulation = value
+		return
+	}
+	defer recoverError()
+	previous := w.window.GetInputMode(glfw.InputMode(mode))
+	w.window.SetInputMode(glfw.InputMode(mode), value)
+	if mode == CursorMode && value != previous {
+		w.emitCursorMode(value)
+	}
+}
+
+// RawMouseMotionSupported reports whether raw mouse motion can be enabled with the RawMouseMotion input mode.
+func RawMouseMotionSupported() bool {
+	defer recoverError()
+	return glfw.RawMouseMotionSupported()
 }
 
 type Key glfw.Key
 
 const (
+	KeyUnknown      = Key(glfw.KeyUnknown)
 	KeySpace        = Key(glfw.KeySpace)
 	KeyApostrophe   = Key(glfw.KeyApostrophe)
 	KeyComma        = Key(glfw.KeyComma)
@@ -335,6 +647,13 @@ const (
 	MouseButton1 = MouseButton(glfw.MouseButton1)
 	MouseButton2 = MouseButton(glfw.MouseButton2)
 	MouseButton3 = MouseButton(glfw.MouseButton3)
+	MouseButton4 = MouseButton(glfw.MouseButton4)
+	MouseButton5 = MouseButton(glfw.MouseButton5)
+	MouseButton6 = MouseButton(glfw.MouseButton6)
+	MouseButton7 = MouseButton(glfw.MouseButton7)
+	MouseButton8 = MouseButton(glfw.MouseButton8)
+
+	MouseButtonLast = MouseButton(glfw.MouseButtonLast)
 
 	MouseButtonLeft   = MouseButton(glfw.MouseButtonLeft)
 	MouseButtonRight  = MouseButton(glfw.MouseButtonRight)
@@ -355,6 +674,12 @@ const (
 	CursorMode             = InputMode(glfw.CursorMode)
 	StickyKeysMode         = InputMode(glfw.StickyKeysMode)
 	StickyMouseButtonsMode = InputMode(glfw.StickyMouseButtonsMode)
+	LockKeyMods            = InputMode(glfw.LockKeyMods)
+	RawMouseMotion         = InputMode(glfw.RawMouseMotion)
+
+	// TouchMouseEmulationMode is specific to this package. GLFW doesn't deliver touch input
+	// on desktop, where the operating system emulates the mouse instead, so it has no effect.
+	TouchMouseEmulationMode = InputMode(0x00033101)
 )
 
 const (
@@ -366,10 +691,12 @@ const (
 type ModifierKey int
 
 const (
-	ModShift   = ModifierKey(glfw.ModShift)
-	ModControl = ModifierKey(glfw.ModControl)
-	ModAlt     = ModifierKey(glfw.ModAlt)
-	ModSuper   = ModifierKey(glfw.ModSuper)
+	ModShift    = ModifierKey(glfw.ModShift)
+	ModControl  = ModifierKey(glfw.ModControl)
+	ModAlt      = ModifierKey(glfw.ModAlt)
+	ModSuper    = ModifierKey(glfw.ModSuper)
+	ModCapsLock = ModifierKey(glfw.ModCapsLock)
+	ModNumLock  = ModifierKey(glfw.ModNumLock)
 )
 
 // Open opens a named asset. It's the caller's responsibility to close it when done.
@@ -382,139 +709,18 @@ func Open(name string) (io.ReadCloser, error) {
 // ---
 
 func WaitEvents() {
+	defer recoverError()
 	glfw.WaitEvents()
+	pollGestures()
+	refreshInputStates()
 }
 
 func PostEmptyEvent() {
+	defer recoverError()
 	glfw.PostEmptyEvent()
 }
 
 func DefaultWindowHints() {
+	defer recoverError()
 	glfw.DefaultWindowHints()
 }
-
-type CloseCallback func(w *Window)
-
-func (w *Window) SetCloseCallback(cbfun CloseCallback) (previous CloseCallback) {
-	wrappedCbfun := func(_ *glfw.Window) {
-		cbfun(w)
-	}
-
-	p := w.Window.SetCloseCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type RefreshCallback func(w *Window)
-
-func (w *Window) SetRefreshCallback(cbfun RefreshCallback) (previous RefreshCallback) {
-	wrappedCbfun := func(_ *glfw.Window) {
-		cbfun(w)
-	}
-
-	p := w.Window.SetRefreshCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type SizeCallback func(w *Window, width int, height int)
-
-func (w *Window) SetSizeCallback(cbfun SizeCallback) (previous SizeCallback) {
-	wrappedCbfun := func(_ *glfw.Window, width int, height int) {
-		cbfun(w, width, height)
-	}
-
-	p := w.Window.SetSizeCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type CursorEnterCallback func(w *Window, entered bool)
-
-func (w *Window) SetCursorEnterCallback(cbfun CursorEnterCallback) (previous CursorEnterCallback) {
-	wrappedCbfun := func(_ *glfw.Window, entered bool) {
-		cbfun(w, entered)
-	}
-
-	p := w.Window.SetCursorEnterCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type CharModsCallback func(w *Window, char rune, mods ModifierKey)
-
-func (w *Window) SetCharModsCallback(cbfun CharModsCallback) (previous CharModsCallback) {
-	wrappedCbfun := func(_ *glfw.Window, char rune, mods glfw.ModifierKey) {
-		cbfun(w, char, ModifierKey(mods))
-	}
-
-	p := w.Window.SetCharModsCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type PosCallback func(w *Window, xpos int, ypos int)
-
-func (w *Window) SetPosCallback(cbfun PosCallback) (previous PosCallback) {
-	wrappedCbfun := func(_ *glfw.Window, xpos int, ypos int) {
-		cbfun(w, xpos, ypos)
-	}
-
-	p := w.Window.SetPosCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type FocusCallback func(w *Window, focused bool)
-
-func (w *Window) SetFocusCallback(cbfun FocusCallback) (previous FocusCallback) {
-	wrappedCbfun := func(_ *glfw.Window, focused bool) {
-		cbfun(w, focused)
-	}
-
-	p := w.Window.SetFocusCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type IconifyCallback func(w *Window, iconified bool)
-
-func (w *Window) SetIconifyCallback(cbfun IconifyCallback) (previous IconifyCallback) {
-	wrappedCbfun := func(_ *glfw.Window, iconified bool) {
-		cbfun(w, iconified)
-	}
-
-	p := w.Window.SetIconifyCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
-
-type DropCallback func(w *Window, names []string)
-
-func (w *Window) SetDropCallback(cbfun DropCallback) (previous DropCallback) {
-	wrappedCbfun := func(_ *glfw.Window, names []string) {
-		cbfun(w, names)
-	}
-
-	p := w.Window.SetDropCallback(wrappedCbfun)
-	_ = p
-
-	// TODO: Handle previous.
-	return nil
-}
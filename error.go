@@ -0,0 +1,90 @@
+package glfw
+
+import "fmt"
+
+// ErrorCode corresponds to an error code. The values match those of GLFW.
+type ErrorCode int
+
+const (
+	NotInitialized     ErrorCode = 0x00010001 // The library has not been initialized.
+	NoCurrentContext   ErrorCode = 0x00010002 // No context is current.
+	InvalidEnum        ErrorCode = 0x00010003 // One of the enum parameters for the function was given an invalid enum.
+	InvalidValue       ErrorCode = 0x00010004 // One of the parameters for the function was given an invalid value.
+	OutOfMemory        ErrorCode = 0x00010005 // A memory allocation failed.
+	APIUnavailable     ErrorCode = 0x00010006 // The requested client API is unavailable, e.g., the browser doesn't support WebGL.
+	VersionUnavailable ErrorCode = 0x00010007 // The requested client API version is unavailable.
+	PlatformError      ErrorCode = 0x00010008 // A platform-specific error occurred, e.g., a browser API is unsupported.
+	FormatUnavailable  ErrorCode = 0x00010009 // The requested format is unavailable.
+)
+
+func (e ErrorCode) String() string {
+	switch e {
+	case NotInitialized:
+		return "NotInitialized"
+	case NoCurrentContext:
+		return "NoCurrentContext"
+	case InvalidEnum:
+		return "InvalidEnum"
+	case InvalidValue:
+		return "InvalidValue"
+	case OutOfMemory:
+		return "OutOfMemory"
+	case APIUnavailable:
+		return "APIUnavailable"
+	case VersionUnavailable:
+		return "VersionUnavailable"
+	case PlatformError:
+		return "PlatformError"
+	case FormatUnavailable:
+		return "FormatUnavailable"
+	default:
+		return fmt.Sprintf("ErrorCode(0x%08X)", int(e))
+	}
+}
+
+// Error makes ErrorCode an error, so that it can be used as the target of errors.Is:
+//
+//	if errors.Is(err, glfw.APIUnavailable) {
+//		// Fall back to a non-GL renderer.
+//	}
+func (e ErrorCode) Error() string {
+	return e.String()
+}
+
+// Error holds an error code and a description of the error.
+type Error struct {
+	Code ErrorCode
+	Desc string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %s", e.Code, e.Desc)
+}
+
+// Is reports whether target is the ErrorCode of e.
+func (e *Error) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == e.Code
+}
+
+// ErrorCallback is called with the code and description of each error that occurs.
+type ErrorCallback func(code ErrorCode, desc string)
+
+var errorCallback ErrorCallback
+
+// SetErrorCallback sets the error callback, which is called with the code and description
+// of each error that occurs, including errors that are also returned by the function that
+// caused them. It may be called before Init.
+func SetErrorCallback(cbfun ErrorCallback) (previous ErrorCallback) {
+	previous = errorCallback
+	errorCallback = cbfun
+	return previous
+}
+
+// reportError reports an error to the error callback, and returns it.
+func reportError(code ErrorCode, desc string) error {
+	if errorCallback != nil {
+		errorCallback(code, desc)
+	}
+	return &Error{Code: code, Desc: desc}
+}
@@ -0,0 +1,24 @@
+// +build !js
+
+#include <stddef.h>
+
+#include "_cgo_export.h"
+
+typedef void (*errorfun)(int, const char *);
+
+// Declared rather than included, since the GLFW headers are in the go-gl/glfw module.
+errorfun glfwSetErrorCallback(errorfun callback);
+
+// goglErrorCallback is the error callback set by go-gl/glfw.
+static errorfun goglErrorCallback;
+
+static void errorCallback(int code, const char *desc) {
+	goxjsErrorCallback(code, (char *)desc);
+	if (goglErrorCallback != NULL) {
+		goglErrorCallback(code, desc);
+	}
+}
+
+void goxjsSetErrorCallback(void) {
+	goglErrorCallback = glfwSetErrorCallback(errorCallback);
+}
@@ -0,0 +1,20 @@
+// +build !js
+
+package glfw
+
+// void goxjsSetErrorCallback(void);
+import "C"
+
+// go-gl/glfw logs platform errors instead of returning them or panicking, so they're reported
+// by an error callback that's called before its own, which still gets every error.
+func init() {
+	C.goxjsSetErrorCallback()
+}
+
+//export goxjsErrorCallback
+func goxjsErrorCallback(code C.int, desc *C.char) {
+	// Other errors are returned or panicked with by go-gl/glfw, and reported from there.
+	if ErrorCode(code) == PlatformError {
+		reportError(PlatformError, C.GoString(desc))
+	}
+}
@@ -0,0 +1,130 @@
+// +build js
+
+package glfw
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+)
+
+// fullscreenAPI holds the names of the methods, properties and events of a variant of the Fullscreen API.
+type fullscreenAPI struct {
+	request, exit, element, change, error string
+}
+
+// fullscreenAPIs are the variants of the Fullscreen API, in order of preference.
+var fullscreenAPIs = []fullscreenAPI{
+	{"requestFullscreen", "exitFullscreen", "fullscreenElement", "fullscreenchange", "fullscreenerror"},
+	{"webkitRequestFullscreen", "webkitExitFullscreen", "webkitFullscreenElement", "webkitfullscreenchange", "webkitfullscreenerror"},
+	{"mozRequestFullScreen", "mozCancelFullScreen", "mozFullScreenElement", "mozfullscreenchange", "mozfullscreenerror"},
+	{"msRequestFullscreen", "msExitFullscreen", "msFullscreenElement", "MSFullscreenChange", "MSFullscreenError"},
+}
+
+// findFullscreenAPI returns the variant of the Fullscreen API the browser supports, or nil if there's none.
+func findFullscreenAPI(canvas *dom.HTMLCanvasElement) *fullscreenAPI {
+	for i, api := range fullscreenAPIs {
+		if canvas.Underlying().Get(api.request) != js.Undefined &&
+			document.Underlying().Get(api.exit) != js.Undefined {
+
+			return &fullscreenAPIs[i]
+		}
+	}
+	return nil
+}
+
+// initFullscreen looks up the Fullscreen API, and keeps track of the fullscreen state of the window,
+// which also changes when the user leaves fullscreen, e.g., by pressing Esc.
+func (w *Window) initFullscreen() {
+	w.fullscreenAPI = findFullscreenAPI(w.canvas)
+	if w.fullscreenAPI == nil {
+		w.missing.fullscreen = true
+		return
+	}
+
+	document.AddEventListener(w.fullscreenAPI.change, false, func(dom.Event) {
+		fullscreen := document.Underlying().Get(w.fullscreenAPI.element) == w.canvas.Underlying()
+		if fullscreen == w.fullscreen {
+			return
+		}
+		w.fullscreen = fullscreen
+		if fullscreen {
+			w.lockKeyboard()
+		} else {
+			w.unlockKeyboard()
+		}
+		w.queueEvent(func() { w.emitFullscreen(fullscreen) })
+	})
+	document.AddEventListener(w.fullscreenAPI.error, false, func(dom.Event) {
+		// The browser doesn't say why; usually it's because the request wasn't made from a user input handler.
+		go reportError(PlatformError, "fullscreen request denied")
+	})
+}
+
+// SetFullscreen makes the window fullscreen, or leaves fullscreen.
+//
+// Browsers only allow entering fullscreen in response to user input, so if SetFullscreen isn't
+// called shortly after a user input event, fullscreen is entered at the next one. Failures
+// that happen then are reported to the error callback, and changes of the fullscreen state,
+// including those made by the user, to the fullscreen callback.
+func (w *Window) SetFullscreen(fullscreen bool) error {
+	if w.missing.fullscreen {
+		return reportError(PlatformError, "Fullscreen API unsupported")
+	}
+	if !fullscreen {
+		w.requestFullscreen = false
+		if w.fullscreen {
+			document.Underlying().Call(w.fullscreenAPI.exit)
+		}
+		return nil
+	}
+	if w.fullscreen {
+		return nil
+	}
+	if hasUserActivation() {
+		w.enterFullscreen()
+	} else {
+		w.requestFullscreen = true
+	}
+	return nil
+}
+
+// SetMonitor makes the window fullscreen if monitor is non-nil, and leaves fullscreen otherwise.
+// Browsers don't let pages choose the monitor, position, size or refresh rate, so those are ignored.
+func (w *Window) SetMonitor(monitor *Monitor, xpos, ypos, width, height, refreshRate int) {
+	w.SetFullscreen(monitor != nil)
+}
+
+// GetMonitor returns the monitor of the window if it's fullscreen, or nil otherwise.
+func (w *Window) GetMonitor() *Monitor {
+	if !w.fullscreen {
+		return nil
+	}
+	return &Monitor{}
+}
+
+// goFullscreenIfRequested enters fullscreen if it was requested. It is called only from
+// user input handlers, because the Fullscreen API fails if called at any other time.
+func (w *Window) goFullscreenIfRequested() {
+	if !w.requestFullscreen {
+		return
+	}
+	w.requestFullscreen = false
+	w.enterFullscreen()
+}
+
+// enterFullscreen requests fullscreen. The result is reported by fullscreen change and error events.
+func (w *Window) enterFullscreen() {
+	promise := w.canvas.Underlying().Call(w.fullscreenAPI.request)
+	if promise != nil && promise != js.Undefined && promise.Get("catch") != js.Undefined {
+		// Failures are also reported by the error event, which older browsers only have.
+		promise.Call("catch", func(*js.Object) {})
+	}
+}
+
+// hasUserActivation reports whether the page is handling user input, which some browser APIs require.
+// Callbacks run shortly after the input event that caused them, which browsers still count as
+// handling it. It's false if the browser can't tell, so that the caller can wait for the next input.
+func hasUserActivation() bool {
+	userActivation := js.Global.Get("navigator").Get("userActivation")
+	return userActivation != js.Undefined && userActivation.Get("isActive").Bool()
+}
@@ -0,0 +1,284 @@
+package glfw
+
+import (
+	"math"
+	"time"
+)
+
+// GestureType is the type of a recognized gesture.
+type GestureType int
+
+const (
+	GesturePinch     GestureType = iota // Two touch points moving apart or together, e.g., to zoom.
+	GesturePan                          // Two touch points moving together, or precise scrolling, e.g., to pan or scroll.
+	GestureLongPress                    // A single touch point held in place, to be treated as a secondary click.
+)
+
+// Gesture describes a recognized gesture. Positions are in screen coordinates relative to the window,
+// like those of CursorPosCallback.
+type Gesture struct {
+	Type GestureType
+
+	// Scale is the factor by which the distance between the touch points changed
+	// since the previous pinch event. It's 1 for other gestures.
+	Scale float64
+
+	// TranslationX and TranslationY are the movement since the previous pan event.
+	// They're 0 for other gestures.
+	TranslationX, TranslationY float64
+
+	// CentroidX and CentroidY are the position of the gesture: the point midway between
+	// the touch points, the cursor position when scrolling, or the position of a long press.
+	CentroidX, CentroidY float64
+}
+
+// GestureConfig holds the thresholds used to recognize gestures.
+type GestureConfig struct {
+	PinchThreshold     float64       // Change in distance between two touch points before a pinch is recognized.
+	PanThreshold       float64       // Movement of the centroid of two touch points before a pan is recognized.
+	LongPressDuration  time.Duration // Time a single touch point must be held before a long press is recognized.
+	LongPressTolerance float64       // Movement of a touch point that cancels a long press.
+	ScrollScale        float64       // Translation of a pan per pixel of precise scrolling.
+
+	// LongPressClick makes a long press also deliver a press and release of MouseButtonRight,
+	// preceded by a cursor position event at the touch point.
+	LongPressClick bool
+}
+
+// DefaultGestureConfig is the configuration used by a window until SetGestureConfig is called.
+var DefaultGestureConfig = GestureConfig{
+	PinchThreshold:     10,
+	PanThreshold:       10,
+	LongPressDuration:  500 * time.Millisecond,
+	LongPressTolerance: 10,
+	ScrollScale:        1,
+	LongPressClick:     true,
+}
+
+// GestureCallback is called when a gesture is recognized. Pinch and pan events are delivered
+// continuously while the gesture is in progress.
+//
+// Gestures are recognized from touch input in the browser, and from precise scroll events on
+// both backends, so that two-finger trackpad scrolling pans while mouse wheel notches don't.
+// See PreciseScrollCallback for how precise scrolling is told apart. GLFW doesn't report
+// trackpad pinches, so pinch gestures aren't available on desktop.
+//
+// A long press is recognized when the next event of the touch point is processed, or when
+// PollEvents or WaitEvents is called, once LongPressDuration has passed.
+type GestureCallback func(w *Window, gesture Gesture)
+
+func (w *Window) SetGestureCallback(cbfun GestureCallback) (previous GestureCallback) {
+	w.gestureRecognizer()
+	previous = w.gestureCallback
+	w.gestureCallback = cbfun
+	return previous
+}
+
+// SetGestureConfig sets the thresholds used to recognize gestures.
+func (w *Window) SetGestureConfig(config GestureConfig) {
+	w.gestureRecognizer().config = config
+}
+
+func (w *Window) emitGesture(gesture Gesture) {
+	for _, l := range w.listeners {
+		if l.gestureCallback != nil {
+			l.gestureCallback(w, gesture)
+		}
+	}
+	if w.gestureCallback != nil {
+		w.gestureCallback(w, gesture)
+	}
+}
+
+// gestureRecognizer returns the gesture recognizer of w, creating it on first use.
+func (w *Window) gestureRecognizer() *gestureRecognizer {
+	if w.gestures == nil {
+		w.gestures = newGestureRecognizer(w)
+		gestureRecognizers[w] = w.gestures
+	}
+	return w.gestures
+}
+
+// gestureRecognizers holds the gesture recognizers of windows, by window,
+// so that PollEvents and WaitEvents can recognize long presses.
+var gestureRecognizers = make(map[*Window]*gestureRecognizer)
+
+// pollGestures recognizes the long presses of all windows whose duration has passed.
+// It's called by PollEvents and WaitEvents, before the input states are refreshed.
+func pollGestures() {
+	for w, g := range gestureRecognizers {
+		w.queueEvent(g.checkLongPress)
+	}
+}
+
+// gestureRecognizer turns the touch and scroll events of a window into gestures.
+// It observes them as a listener, so replaying a recording also replays gestures.
+type gestureRecognizer struct {
+	w      *Window
+	config GestureConfig
+
+	touches   map[int][2]float64 // Positions of the touch points on the surface, by id.
+	cursorPos [2]float64
+
+	// Two-finger gesture state, valid while exactly two touch points are on the surface.
+	startDist, lastDist         float64
+	startCentroid, lastCentroid [2]float64
+	pinching, panning           bool
+
+	// Long press state, valid while longPressing is true.
+	longPressing   bool
+	longPressStart time.Time
+	longPressPos   [2]float64
+}
+
+func newGestureRecognizer(w *Window) *gestureRecognizer {
+	g := &gestureRecognizer{
+		w:       w,
+		config:  DefaultGestureConfig,
+		touches: make(map[int][2]float64),
+	}
+	w.addListener(&callbacks{
+		touchCallback: func(_ *Window, id int, phase TouchPhase, x float64, y float64) {
+			g.touch(id, phase, x, y)
+		},
+		cursorPosCallback: func(_ *Window, xpos float64, ypos float64) {
+			g.cursorPos = [2]float64{xpos, ypos}
+		},
+		preciseScrollCallback: func(_ *Window, xpixels float64, ypixels float64, precise bool) {
+			if !precise {
+				return
+			}
+			g.w.emitGesture(Gesture{
+				Type:         GesturePan,
+				Scale:        1,
+				TranslationX: xpixels * g.config.ScrollScale,
+				TranslationY: ypixels * g.config.ScrollScale,
+				CentroidX:    g.cursorPos[0],
+				CentroidY:    g.cursorPos[1],
+			})
+		},
+	})
+	return g
+}
+
+func (g *gestureRecognizer) touch(id int, phase TouchPhase, x, y float64) {
+	// Recognize a pending long press first, since it happened before this event.
+	g.checkLongPress()
+
+	switch phase {
+	case TouchBegan:
+		g.touches[id] = [2]float64{x, y}
+		g.cancelLongPress()
+		if len(g.touches) == 1 {
+			g.startLongPress(x, y)
+		}
+		g.resetTwoFinger()
+	case TouchMoved:
+		if _, ok := g.touches[id]; !ok {
+			return
+		}
+		g.touches[id] = [2]float64{x, y}
+		if g.longPressing && math.Hypot(x-g.longPressPos[0], y-g.longPressPos[1]) > g.config.LongPressTolerance {
+			g.cancelLongPress()
+		}
+		g.moveTwoFinger()
+	case TouchEnded, TouchCancelled:
+		delete(g.touches, id)
+		g.cancelLongPress()
+		g.resetTwoFinger()
+	}
+}
+
+// resetTwoFinger starts tracking a new two-finger gesture, if exactly two touch points are on the surface.
+func (g *gestureRecognizer) resetTwoFinger() {
+	g.pinching, g.panning = false, false
+	if len(g.touches) != 2 {
+		return
+	}
+	g.startDist, g.startCentroid = g.twoFinger()
+	g.lastDist, g.lastCentroid = g.startDist, g.startCentroid
+}
+
+func (g *gestureRecognizer) moveTwoFinger() {
+	if len(g.touches) != 2 {
+		return
+	}
+	dist, centroid := g.twoFinger()
+
+	if !g.pinching && math.Abs(dist-g.startDist) >= g.config.PinchThreshold {
+		g.pinching = true
+	}
+	if !g.panning && math.Hypot(centroid[0]-g.startCentroid[0], centroid[1]-g.startCentroid[1]) >= g.config.PanThreshold {
+		g.panning = true
+	}
+
+	if g.pinching && g.lastDist > 0 && dist != g.lastDist {
+		g.w.emitGesture(Gesture{
+			Type:      GesturePinch,
+			Scale:     dist / g.lastDist,
+			CentroidX: centroid[0],
+			CentroidY: centroid[1],
+		})
+		g.lastDist = dist
+	}
+	if g.panning && centroid != g.lastCentroid {
+		g.w.emitGesture(Gesture{
+			Type:         GesturePan,
+			Scale:        1,
+			TranslationX: centroid[0] - g.lastCentroid[0],
+			TranslationY: centroid[1] - g.lastCentroid[1],
+			CentroidX:    centroid[0],
+			CentroidY:    centroid[1],
+		})
+		g.lastCentroid = centroid
+	}
+}
+
+// twoFinger returns the distance between, and the centroid of, the two touch points on the surface.
+func (g *gestureRecognizer) twoFinger() (dist float64, centroid [2]float64) {
+	var p [2][2]float64
+	i := 0
+	for _, pos := range g.touches {
+		p[i] = pos
+		i++
+	}
+	dist = math.Hypot(p[1][0]-p[0][0], p[1][1]-p[0][1])
+	centroid = [2]float64{(p[0][0] + p[1][0]) / 2, (p[0][1] + p[1][1]) / 2}
+	return dist, centroid
+}
+
+// startLongPress starts timing a long press at x, y.
+func (g *gestureRecognizer) startLongPress(x, y float64) {
+	g.longPressing = true
+	g.longPressStart = time.Now()
+	g.longPressPos = [2]float64{x, y}
+}
+
+// checkLongPress recognizes the pending long press, if its duration has passed.
+// It's driven by events rather than by a timer, so that the gesture is delivered
+// in order with the events of the window, on the goroutine that delivers them.
+func (g *gestureRecognizer) checkLongPress() {
+	if !g.longPressing || time.Since(g.longPressStart) < g.config.LongPressDuration {
+		return
+	}
+	g.longPressing = false
+	x, y := g.longPressPos[0], g.longPressPos[1]
+	g.w.emitGesture(Gesture{
+		Type:      GestureLongPress,
+		Scale:     1,
+		CentroidX: x,
+		CentroidY: y,
+	})
+	if g.config.LongPressClick {
+		// Replaying the touches makes the click up again, so it isn't recorded.
+		g.w.synthetic = true
+		g.w.emitCursorPos(x, y)
+		g.w.emitMouseButton(MouseButtonRight, Press, 0)
+		g.w.emitMouseButton(MouseButtonRight, Release, 0)
+		g.w.synthetic = false
+	}
+}
+
+func (g *gestureRecognizer) cancelLongPress() {
+	g.longPressing = false
+}
@@ -1,3 +1,5 @@
 module github.com/goxjs/glfw
 
 go 1.19
+
+require github.com/go-gl/glfw/v3.3/glfw v0.0.0-20260823155953-d41da22a9587
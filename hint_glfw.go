@@ -15,6 +15,10 @@ const (
 	Samples     = Hint(glfw.Samples)
 	Resizable   = Hint(glfw.Resizable)
 
+	// Window attributes, for GetAttrib.
+	Focused   = Hint(glfw.Focused)
+	Iconified = Hint(glfw.Iconified)
+
 	// These hints used for WebGL contexts, ignored on desktop.
 	PremultipliedAlpha = noopHint
 	PreserveDrawingBuffer
@@ -33,6 +37,7 @@ func WindowHint(target Hint, hint int) {
 	if target == noopHint {
 		return
 	}
+	defer recoverError()
 
 	glfw.WindowHint(glfw.Hint(target), hint)
 }
@@ -7,7 +7,9 @@ var hints = make(map[Hint]int)
 type Hint int
 
 const (
-	AlphaBits Hint = iota
+	ClientAPI Hint = iota
+
+	AlphaBits
 	DepthBits
 	StencilBits
 	Samples
@@ -18,6 +20,15 @@ const (
 	PreserveDrawingBuffer
 	PreferLowPowerToHighPerformance
 	FailIfMajorPerformanceCaveat
+
+	// Window attributes, for GetAttrib.
+	Focused
+	Iconified
+)
+
+const (
+	// NoAPI can be used with the ClientAPI hint to create a canvas without a WebGL context.
+	NoAPI int = iota + 1
 )
 
 func WindowHint(target Hint, hint int) {
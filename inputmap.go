@@ -0,0 +1,373 @@
+package glfw
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Device is the type of device a Binding is for.
+type Device int
+
+const (
+	DeviceKeyboard Device = iota
+	DeviceMouse
+)
+
+// Binding is a physical input that triggers an action: a key or a mouse button,
+// along with modifier keys that must be held.
+//
+// Its text form is the names of the modifier keys and of the key or mouse button,
+// joined by "+", e.g., "Ctrl+S" or "Shift+MouseLeft".
+type Binding struct {
+	Device Device
+	Key    Key         // Key, for DeviceKeyboard.
+	Button MouseButton // Mouse button, for DeviceMouse.
+	Mods   ModifierKey // Modifier keys that must be held, other than lock keys. Others may be held, too.
+}
+
+// lockModifiers are the lock key modifiers. Bindings can't require them, since InputState doesn't track them.
+const lockModifiers = ModCapsLock | ModNumLock
+
+// KeyBinding returns a binding for key with mods held.
+func KeyBinding(key Key, mods ModifierKey) Binding {
+	return Binding{Device: DeviceKeyboard, Key: key, Mods: mods}
+}
+
+// MouseBinding returns a binding for button with mods held.
+func MouseBinding(button MouseButton, mods ModifierKey) Binding {
+	return Binding{Device: DeviceMouse, Button: button, Mods: mods}
+}
+
+// ParseBinding parses the text form of a binding. Case is ignored, and common aliases
+// of modifier keys, such as "Control" and "Cmd", are accepted.
+func ParseBinding(s string) (Binding, error) {
+	var b Binding
+	err := b.UnmarshalText([]byte(s))
+	return b, err
+}
+
+func (b Binding) String() string {
+	name := b.Key.String()
+	if b.Device == DeviceMouse {
+		name = b.Button.String()
+	}
+	mods := b.Mods &^ lockModifiers
+	if mods == 0 {
+		return name
+	}
+	return mods.String() + "+" + name
+}
+
+func (b Binding) MarshalText() ([]byte, error) {
+	var err error
+	switch b.Device {
+	case DeviceKeyboard:
+		_, err = b.Key.MarshalText()
+	case DeviceMouse:
+		_, err = b.Button.MarshalText()
+	default:
+		err = fmt.Errorf("can't marshal binding for unknown device %d", int(b.Device))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+func (b *Binding) UnmarshalText(text []byte) error {
+	parts := strings.Split(strings.TrimSpace(string(text)), "+")
+	name := strings.ToLower(strings.TrimSpace(parts[len(parts)-1]))
+
+	var mods ModifierKey
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := modifiersByName[strings.ToLower(strings.TrimSpace(part))]
+		if !ok || mod&lockModifiers != 0 {
+			return fmt.Errorf("unknown modifier key %q in binding %q", part, text)
+		}
+		mods |= mod
+	}
+
+	if key, ok := keysByName[name]; ok {
+		*b = KeyBinding(key, mods)
+		return nil
+	}
+	if button, ok := mouseButtonsByName[name]; ok {
+		*b = MouseBinding(button, mods)
+		return nil
+	}
+	return fmt.Errorf("unknown key or mouse button %q in binding %q", parts[len(parts)-1], text)
+}
+
+func (b Binding) down(s *InputState) bool {
+	if mods := b.Mods &^ lockModifiers; s.Mods()&mods != mods {
+		return false
+	}
+	if b.Device == DeviceMouse {
+		return s.MouseDown(b.Button)
+	}
+	return s.Down(b.Key)
+}
+
+func (b Binding) pressed(s *InputState) bool {
+	if mods := b.Mods &^ lockModifiers; s.Mods()&mods != mods {
+		return false
+	}
+	if b.Device == DeviceMouse {
+		return s.MousePressed(b.Button)
+	}
+	return s.Pressed(b.Key)
+}
+
+// released ignores modifier keys, since they're often released first.
+func (b Binding) released(s *InputState) bool {
+	if b.Device == DeviceMouse {
+		return s.MouseReleased(b.Button)
+	}
+	return s.Released(b.Key)
+}
+
+// Axis is a pair of binding lists that drive a value between -1 and 1, e.g., for movement.
+type Axis struct {
+	Negative []Binding `json:"negative,omitempty"`
+	Positive []Binding `json:"positive,omitempty"`
+}
+
+// InputMap maps named actions and axes to the bindings that trigger them. Bindings can be
+// changed at any time, e.g., to let players rebind them, and stored as JSON or as text.
+//
+// Actions and axes are queried with the InputState of a window, so they have the same
+// per-frame semantics:
+//
+//	input := window.InputState()
+//	for !window.ShouldClose() {
+//		glfw.PollEvents()
+//		if inputMap.Pressed(input, "jump") {
+//			// Jump.
+//		}
+//		x := inputMap.Axis(input, "move")
+//		...
+//	}
+type InputMap struct {
+	Actions map[string][]Binding `json:"actions,omitempty"`
+	Axes    map[string]Axis      `json:"axes,omitempty"`
+}
+
+// NewInputMap returns an empty input map.
+func NewInputMap() *InputMap {
+	return &InputMap{
+		Actions: make(map[string][]Binding),
+		Axes:    make(map[string]Axis),
+	}
+}
+
+// Bind adds bindings to action.
+func (m *InputMap) Bind(action string, bindings ...Binding) {
+	if m.Actions == nil {
+		m.Actions = make(map[string][]Binding)
+	}
+	m.Actions[action] = append(m.Actions[action], bindings...)
+}
+
+// BindAxis adds a pair of bindings to axis: negative drives it to -1, positive to 1.
+func (m *InputMap) BindAxis(axis string, negative, positive Binding) {
+	if m.Axes == nil {
+		m.Axes = make(map[string]Axis)
+	}
+	a := m.Axes[axis]
+	a.Negative = append(a.Negative, negative)
+	a.Positive = append(a.Positive, positive)
+	m.Axes[axis] = a
+}
+
+// Down reports whether any binding of action was held down at the end of the frame.
+func (m *InputMap) Down(s *InputState, action string) bool {
+	return anyBinding(m.Actions[action], s, Binding.down)
+}
+
+// Pressed reports whether any binding of action was pressed during the frame.
+func (m *InputMap) Pressed(s *InputState, action string) bool {
+	return anyBinding(m.Actions[action], s, Binding.pressed)
+}
+
+// Released reports whether any binding of action was released during the frame.
+func (m *InputMap) Released(s *InputState, action string) bool {
+	return anyBinding(m.Actions[action], s, Binding.released)
+}
+
+// Axis returns the value of axis at the end of the frame: -1 if a negative binding is held,
+// 1 if a positive one is, and 0 if both or neither are.
+func (m *InputMap) Axis(s *InputState, axis string) float64 {
+	a := m.Axes[axis]
+	var value float64
+	if anyBinding(a.Negative, s, Binding.down) {
+		value--
+	}
+	if anyBinding(a.Positive, s, Binding.down) {
+		value++
+	}
+	return value
+}
+
+func anyBinding(bindings []Binding, s *InputState, f func(Binding, *InputState) bool) bool {
+	for _, b := range bindings {
+		if f(b, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// The text form of an input map has one action or axis per line, sorted by name:
+//
+//	action fire = MouseLeft, Ctrl+F
+//	axis move = -A, -Left, +D, +Right
+//
+// Axis bindings are prefixed with - or + for their direction. Names that are empty
+// or contain spaces, "=" or quotes are quoted, as in Go, e.g., action "fire weapon".
+// Empty lines and lines starting with # are ignored.
+
+// Text returns the text form of m.
+func (m *InputMap) Text() (string, error) {
+	var buf strings.Builder
+	actions := make([]string, 0, len(m.Actions))
+	for action := range m.Actions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		var names []string
+		for _, b := range m.Actions[action] {
+			text, err := b.MarshalText()
+			if err != nil {
+				return "", err
+			}
+			names = append(names, string(text))
+		}
+		fmt.Fprintf(&buf, "action %s = %s\n", formatInputMapName(action), strings.Join(names, ", "))
+	}
+	axes := make([]string, 0, len(m.Axes))
+	for axis := range m.Axes {
+		axes = append(axes, axis)
+	}
+	sort.Strings(axes)
+	for _, axis := range axes {
+		var names []string
+		for _, dir := range []struct {
+			prefix   string
+			bindings []Binding
+		}{
+			{"-", m.Axes[axis].Negative},
+			{"+", m.Axes[axis].Positive},
+		} {
+			for _, b := range dir.bindings {
+				text, err := b.MarshalText()
+				if err != nil {
+					return "", err
+				}
+				names = append(names, dir.prefix+string(text))
+			}
+		}
+		fmt.Fprintf(&buf, "axis %s = %s\n", formatInputMapName(axis), strings.Join(names, ", "))
+	}
+	return buf.String(), nil
+}
+
+// ParseInputMap parses the text form of an input map, as returned by InputMap.Text.
+func ParseInputMap(r io.Reader) (*InputMap, error) {
+	m := NewInputMap()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		kind, rest := text, ""
+		if i := strings.IndexFunc(text, unicode.IsSpace); i != -1 {
+			kind, rest = text[:i], strings.TrimSpace(text[i:])
+		}
+		name, list, err := parseInputMapName(rest)
+		if err != nil {
+			return nil, fmt.Errorf("input map line %d: %v", line, err)
+		}
+		var bindings []string
+		if list = strings.TrimSpace(list); list != "" {
+			bindings = strings.Split(list, ",")
+		}
+		switch kind {
+		case "action":
+			m.Actions[name] = nil
+			for _, s := range bindings {
+				b, err := ParseBinding(s)
+				if err != nil {
+					return nil, fmt.Errorf("input map line %d: %v", line, err)
+				}
+				m.Bind(name, b)
+			}
+		case "axis":
+			var a Axis
+			for _, s := range bindings {
+				s = strings.TrimSpace(s)
+				if s == "" || (s[0] != '-' && s[0] != '+') {
+					return nil, fmt.Errorf("input map line %d: axis binding %q must start with - or +", line, s)
+				}
+				b, err := ParseBinding(s[1:])
+				if err != nil {
+					return nil, fmt.Errorf("input map line %d: %v", line, err)
+				}
+				if s[0] == '-' {
+					a.Negative = append(a.Negative, b)
+				} else {
+					a.Positive = append(a.Positive, b)
+				}
+			}
+			m.Axes[name] = a
+		default:
+			return nil, fmt.Errorf("input map line %d: unknown kind %q, expected action or axis", line, kind)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// formatInputMapName returns name as written in the text form of an input map,
+// quoted if it would otherwise not be read back as is.
+func formatInputMapName(name string) string {
+	quoted := strconv.Quote(name)
+	if name == "" || quoted != `"`+name+`"` || strings.ContainsAny(name, "= ") {
+		return quoted
+	}
+	return name
+}
+
+// parseInputMapName parses the name at the start of s, which is followed by "=" and
+// a list of bindings, and returns the name and the list.
+func parseInputMapName(s string) (name, list string, err error) {
+	if strings.HasPrefix(s, `"`) {
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid quoted name: %v", err)
+		}
+		name, _ = strconv.Unquote(quoted)
+		s = strings.TrimSpace(s[len(quoted):])
+		if !strings.HasPrefix(s, "=") {
+			return "", "", fmt.Errorf("missing = after name")
+		}
+		return name, s[1:], nil
+	}
+	eq := strings.Index(s, "=")
+	if eq == -1 {
+		return "", "", fmt.Errorf("missing =")
+	}
+	fields := strings.Fields(s[:eq])
+	if len(fields) != 1 {
+		return "", "", fmt.Errorf("expected action or axis followed by a name")
+	}
+	return fields[0], s[eq+1:], nil
+}
@@ -0,0 +1,40 @@
+package glfw
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInputMapTextRoundTrip(t *testing.T) {
+	m := NewInputMap()
+	m.Bind("jump", KeyBinding(KeySpace, 0))
+	m.Bind("fire weapon", MouseBinding(MouseButtonLeft, 0), KeyBinding(KeyF, ModControl))
+	m.Bind("a=b", KeyBinding(KeyA, ModShift|ModAlt))
+	m.Bind(`say "hi"`, KeyBinding(KeyH, 0))
+	m.Bind("", KeyBinding(KeyEnter, 0))
+	m.Bind("none")
+	m.BindAxis("move", KeyBinding(KeyA, 0), KeyBinding(KeyD, 0))
+	m.BindAxis("move", KeyBinding(KeyLeft, 0), KeyBinding(KeyRight, 0))
+	m.BindAxis("zoom level", KeyBinding(KeyMinus, ModControl), KeyBinding(KeyEqual, ModControl))
+
+	text, err := m.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseInputMap(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ParseInputMap(%q): %v", text, err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("ParseInputMap(%q) = %+v, want %+v", text, got, m)
+	}
+}
+
+func TestParseBindingLockModifiers(t *testing.T) {
+	for _, s := range []string{"CapsLock+A", "NumLock+MouseLeft"} {
+		if b, err := ParseBinding(s); err == nil {
+			t.Errorf("ParseBinding(%q) = %v, want error", s, b)
+		}
+	}
+}
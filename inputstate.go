@@ -0,0 +1,206 @@
+package glfw
+
+import "sync"
+
+// InputState is a snapshot of the input a window received during a frame, with edge detection.
+// A frame is the time between two calls of PollEvents or WaitEvents. It can be read from any
+// goroutine, e.g., from the function passed to Main.
+type InputState struct {
+	mu sync.RWMutex // Guards the frame, which PollEvents and WaitEvents replace.
+	inputFrame
+}
+
+// inputFrame is the input of a window during a frame.
+type inputFrame struct {
+	keysDown, keysPressed, keysReleased          map[Key]bool
+	buttonsDown, buttonsPressed, buttonsReleased map[MouseButton]bool
+
+	cursorDelta [2]float64
+	scrollDelta [2]float64
+	text        []rune
+}
+
+func newInputFrame() *inputFrame {
+	return &inputFrame{
+		keysDown:        make(map[Key]bool),
+		keysPressed:     make(map[Key]bool),
+		keysReleased:    make(map[Key]bool),
+		buttonsDown:     make(map[MouseButton]bool),
+		buttonsPressed:  make(map[MouseButton]bool),
+		buttonsReleased: make(map[MouseButton]bool),
+	}
+}
+
+// Down reports whether key was held down at the end of the frame.
+func (s *InputState) Down(key Key) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keysDown[key]
+}
+
+// Pressed reports whether key was pressed during the frame. Key repeats don't count.
+func (s *InputState) Pressed(key Key) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keysPressed[key]
+}
+
+// Released reports whether key was released during the frame.
+// A key that was pressed and released within one frame is both pressed and released.
+func (s *InputState) Released(key Key) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keysReleased[key]
+}
+
+// Mods returns the modifier keys held down at the end of the frame.
+// Lock key modifiers aren't included.
+func (s *InputState) Mods() ModifierKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var mods ModifierKey
+	for _, m := range []struct {
+		left, right Key
+		mod         ModifierKey
+	}{
+		{KeyLeftShift, KeyRightShift, ModShift},
+		{KeyLeftControl, KeyRightControl, ModControl},
+		{KeyLeftAlt, KeyRightAlt, ModAlt},
+		{KeyLeftSuper, KeyRightSuper, ModSuper},
+	} {
+		if s.keysDown[m.left] || s.keysDown[m.right] {
+			mods |= m.mod
+		}
+	}
+	return mods
+}
+
+// MouseDown reports whether button was held down at the end of the frame.
+func (s *InputState) MouseDown(button MouseButton) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.buttonsDown[button]
+}
+
+// MousePressed reports whether button was pressed during the frame.
+func (s *InputState) MousePressed(button MouseButton) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.buttonsPressed[button]
+}
+
+// MouseReleased reports whether button was released during the frame.
+func (s *InputState) MouseReleased(button MouseButton) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.buttonsReleased[button]
+}
+
+// CursorDelta returns the accumulated cursor movement during the frame, in screen coordinates.
+// It's reported while the cursor is disabled, too.
+func (s *InputState) CursorDelta() (dx, dy float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cursorDelta[0], s.cursorDelta[1]
+}
+
+// ScrollDelta returns the accumulated scroll offsets during the frame.
+func (s *InputState) ScrollDelta() (xoff, yoff float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.scrollDelta[0], s.scrollDelta[1]
+}
+
+// Text returns the text typed during the frame.
+func (s *InputState) Text() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return string(s.text)
+}
+
+// inputTrackers holds the input trackers of windows, by window.
+// They're created by InputState, and refreshed by PollEvents and WaitEvents.
+var inputTrackers = make(map[*Window]*inputTracker)
+
+// inputTracker accumulates the events of a window into the InputState of the next frame.
+type inputTracker struct {
+	current  *InputState
+	next     *inputFrame
+	listener *callbacks
+}
+
+// InputState returns the input state of w for the last frame. It's updated in place
+// by PollEvents and WaitEvents. Input is only tracked after InputState is first called,
+// which must be from the main thread, like other methods of Window.
+func (w *Window) InputState() *InputState {
+	t, ok := inputTrackers[w]
+	if !ok {
+		t = newInputTracker()
+		w.addListener(t.listener)
+		inputTrackers[w] = t
+	}
+	return t.current
+}
+
+func newInputTracker() *inputTracker {
+	t := &inputTracker{current: &InputState{inputFrame: *newInputFrame()}, next: newInputFrame()}
+	t.listener = &callbacks{
+		keyCallback: func(_ *Window, key Key, _ int, action Action, _ ModifierKey) {
+			switch action {
+			case Press:
+				t.next.keysDown[key] = true
+				t.next.keysPressed[key] = true
+			case Release:
+				delete(t.next.keysDown, key)
+				t.next.keysReleased[key] = true
+			}
+		},
+		mouseButtonCallback: func(_ *Window, button MouseButton, action Action, _ ModifierKey) {
+			switch action {
+			case Press:
+				t.next.buttonsDown[button] = true
+				t.next.buttonsPressed[button] = true
+			case Release:
+				delete(t.next.buttonsDown, button)
+				t.next.buttonsReleased[button] = true
+			}
+		},
+		mouseMovementCallback: func(_ *Window, _ float64, _ float64, xdelta float64, ydelta float64) {
+			t.next.cursorDelta[0] += xdelta
+			t.next.cursorDelta[1] += ydelta
+		},
+		scrollCallback: func(_ *Window, xoff float64, yoff float64) {
+			t.next.scrollDelta[0] += xoff
+			t.next.scrollDelta[1] += yoff
+		},
+		charCallback: func(_ *Window, char rune) {
+			t.next.text = append(t.next.text, char)
+		},
+	}
+	return t
+}
+
+// refresh makes the accumulated input the current state, and starts accumulating the next frame.
+// The current state is updated in place, so that InputState needs to be called only once.
+func (t *inputTracker) refresh() {
+	next := newInputFrame()
+	for key := range t.next.keysDown {
+		next.keysDown[key] = true
+	}
+	for button := range t.next.buttonsDown {
+		next.buttonsDown[button] = true
+	}
+
+	t.current.mu.Lock()
+	t.current.inputFrame = *t.next
+	t.current.mu.Unlock()
+	t.next = next
+}
+
+// refreshInputStates refreshes the input states of all windows. It's called by PollEvents
+// and WaitEvents once pending events have been delivered.
+func refreshInputStates() {
+	for _, t := range inputTrackers {
+		t.refresh()
+	}
+}
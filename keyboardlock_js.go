@@ -0,0 +1,89 @@
+// +build js
+
+package glfw
+
+import (
+	"fmt"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// keyboardLock holds the state of the Keyboard Lock API for a window.
+type keyboardLock struct {
+	enabled bool     // enabled is true if keys should be locked while the window is fullscreen.
+	codes   []string // KeyboardEvent.code values of the keys to lock, or nil for all keys.
+	active  bool     // active is true while the browser has locked the keys.
+	request int      // request is incremented to invalidate a pending lock request.
+}
+
+// SetKeyboardLock chooses whether keys the browser normally acts on, such as Esc and Ctrl+W,
+// are delivered to the window instead while it's fullscreen. keys are the keys to lock;
+// all keys are locked if there are none. While the keys are locked, the user leaves
+// fullscreen by holding Esc.
+//
+// Keys are locked when the window enters fullscreen, and released when it leaves it.
+// Failures of the browser to lock them are reported to the error callback.
+func (w *Window) SetKeyboardLock(enabled bool, keys ...Key) error {
+	if enabled && !keyboardLockSupported() {
+		return reportError(PlatformError, "Keyboard Lock API unsupported")
+	}
+	var codes []string
+	for _, key := range keys {
+		code, ok := scancodeCodes[GetKeyScancode(key)]
+		if !ok {
+			return reportError(InvalidEnum, fmt.Sprintf("invalid key %v", key))
+		}
+		codes = append(codes, code)
+	}
+
+	w.unlockKeyboard()
+	w.keyboardLock.enabled, w.keyboardLock.codes = enabled, codes
+	if enabled && w.fullscreen {
+		w.lockKeyboard()
+	}
+	return nil
+}
+
+// KeyboardLocked reports whether keys are currently locked by SetKeyboardLock.
+func (w *Window) KeyboardLocked() bool {
+	return w.keyboardLock.active
+}
+
+// lockKeyboard asks the browser to lock the keys, if keyboard lock is enabled.
+func (w *Window) lockKeyboard() {
+	if !w.keyboardLock.enabled {
+		return
+	}
+	var args []interface{}
+	if len(w.keyboardLock.codes) > 0 {
+		args = append(args, w.keyboardLock.codes)
+	}
+
+	w.keyboardLock.request++
+	request := w.keyboardLock.request
+	promise := js.Global.Get("navigator").Get("keyboard").Call("lock", args...)
+	promise.Call("then", func() {
+		if request == w.keyboardLock.request {
+			w.keyboardLock.active = true
+		}
+	}, func(err *js.Object) {
+		go reportError(PlatformError, fmt.Sprintf("keyboard lock failed: %v", err))
+	})
+}
+
+// unlockKeyboard releases the keys, if they're locked or about to be.
+func (w *Window) unlockKeyboard() {
+	w.keyboardLock.request++
+	if !w.keyboardLock.active && !w.keyboardLock.enabled {
+		return
+	}
+	w.keyboardLock.active = false
+	if keyboardLockSupported() {
+		js.Global.Get("navigator").Get("keyboard").Call("unlock")
+	}
+}
+
+func keyboardLockSupported() bool {
+	keyboard := js.Global.Get("navigator").Get("keyboard")
+	return keyboard != js.Undefined && keyboard.Get("lock") != js.Undefined
+}
@@ -0,0 +1,253 @@
+package glfw
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keys and mouse buttons are named after their constants, without the Key prefix.
+// Names are the same on every backend, unlike their values, so they're used to store
+// input bindings.
+
+var keyNames = map[Key]string{
+	KeySpace:        "Space",
+	KeyApostrophe:   "Apostrophe",
+	KeyComma:        "Comma",
+	KeyMinus:        "Minus",
+	KeyPeriod:       "Period",
+	KeySlash:        "Slash",
+	Key0:            "0",
+	Key1:            "1",
+	Key2:            "2",
+	Key3:            "3",
+	Key4:            "4",
+	Key5:            "5",
+	Key6:            "6",
+	Key7:            "7",
+	Key8:            "8",
+	Key9:            "9",
+	KeySemicolon:    "Semicolon",
+	KeyEqual:        "Equal",
+	KeyA:            "A",
+	KeyB:            "B",
+	KeyC:            "C",
+	KeyD:            "D",
+	KeyE:            "E",
+	KeyF:            "F",
+	KeyG:            "G",
+	KeyH:            "H",
+	KeyI:            "I",
+	KeyJ:            "J",
+	KeyK:            "K",
+	KeyL:            "L",
+	KeyM:            "M",
+	KeyN:            "N",
+	KeyO:            "O",
+	KeyP:            "P",
+	KeyQ:            "Q",
+	KeyR:            "R",
+	KeyS:            "S",
+	KeyT:            "T",
+	KeyU:            "U",
+	KeyV:            "V",
+	KeyW:            "W",
+	KeyX:            "X",
+	KeyY:            "Y",
+	KeyZ:            "Z",
+	KeyLeftBracket:  "LeftBracket",
+	KeyBackslash:    "Backslash",
+	KeyRightBracket: "RightBracket",
+	KeyGraveAccent:  "GraveAccent",
+	KeyWorld1:       "World1",
+	KeyWorld2:       "World2",
+	KeyEscape:       "Escape",
+	KeyEnter:        "Enter",
+	KeyTab:          "Tab",
+	KeyBackspace:    "Backspace",
+	KeyInsert:       "Insert",
+	KeyDelete:       "Delete",
+	KeyRight:        "Right",
+	KeyLeft:         "Left",
+	KeyDown:         "Down",
+	KeyUp:           "Up",
+	KeyPageUp:       "PageUp",
+	KeyPageDown:     "PageDown",
+	KeyHome:         "Home",
+	KeyEnd:          "End",
+	KeyCapsLock:     "CapsLock",
+	KeyScrollLock:   "ScrollLock",
+	KeyNumLock:      "NumLock",
+	KeyPrintScreen:  "PrintScreen",
+	KeyPause:        "Pause",
+	KeyF1:           "F1",
+	KeyF2:           "F2",
+	KeyF3:           "F3",
+	KeyF4:           "F4",
+	KeyF5:           "F5",
+	KeyF6:           "F6",
+	KeyF7:           "F7",
+	KeyF8:           "F8",
+	KeyF9:           "F9",
+	KeyF10:          "F10",
+	KeyF11:          "F11",
+	KeyF12:          "F12",
+	KeyF13:          "F13",
+	KeyF14:          "F14",
+	KeyF15:          "F15",
+	KeyF16:          "F16",
+	KeyF17:          "F17",
+	KeyF18:          "F18",
+	KeyF19:          "F19",
+	KeyF20:          "F20",
+	KeyF21:          "F21",
+	KeyF22:          "F22",
+	KeyF23:          "F23",
+	KeyF24:          "F24",
+	KeyF25:          "F25",
+	KeyKP0:          "KP0",
+	KeyKP1:          "KP1",
+	KeyKP2:          "KP2",
+	KeyKP3:          "KP3",
+	KeyKP4:          "KP4",
+	KeyKP5:          "KP5",
+	KeyKP6:          "KP6",
+	KeyKP7:          "KP7",
+	KeyKP8:          "KP8",
+	KeyKP9:          "KP9",
+	KeyKPDecimal:    "KPDecimal",
+	KeyKPDivide:     "KPDivide",
+	KeyKPMultiply:   "KPMultiply",
+	KeyKPSubtract:   "KPSubtract",
+	KeyKPAdd:        "KPAdd",
+	KeyKPEnter:      "KPEnter",
+	KeyKPEqual:      "KPEqual",
+	KeyLeftShift:    "LeftShift",
+	KeyLeftControl:  "LeftControl",
+	KeyLeftAlt:      "LeftAlt",
+	KeyLeftSuper:    "LeftSuper",
+	KeyRightShift:   "RightShift",
+	KeyRightControl: "RightControl",
+	KeyRightAlt:     "RightAlt",
+	KeyRightSuper:   "RightSuper",
+	KeyMenu:         "Menu",
+}
+
+var mouseButtonNames = map[MouseButton]string{
+	MouseButtonLeft:   "MouseLeft",
+	MouseButtonRight:  "MouseRight",
+	MouseButtonMiddle: "MouseMiddle",
+	MouseButton4:      "Mouse4",
+	MouseButton5:      "Mouse5",
+	MouseButton6:      "Mouse6",
+	MouseButton7:      "Mouse7",
+	MouseButton8:      "Mouse8",
+}
+
+// keysByName and mouseButtonsByName map lowercase names back to keys and mouse buttons.
+var (
+	keysByName         = make(map[string]Key, len(keyNames))
+	mouseButtonsByName = make(map[string]MouseButton, len(mouseButtonNames))
+)
+
+func init() {
+	for key, name := range keyNames {
+		keysByName[strings.ToLower(name)] = key
+	}
+	for button, name := range mouseButtonNames {
+		mouseButtonsByName[strings.ToLower(name)] = button
+	}
+}
+
+// String returns the name of the key, e.g., "A", "Space" or "LeftShift".
+func (key Key) String() string {
+	if name, ok := keyNames[key]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key(%d)", int(key))
+}
+
+// MarshalText returns the name of the key.
+func (key Key) MarshalText() ([]byte, error) {
+	name, ok := keyNames[key]
+	if !ok {
+		return nil, fmt.Errorf("can't marshal unknown key %d", int(key))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText sets the key from its name. Case is ignored.
+func (key *Key) UnmarshalText(text []byte) error {
+	k, ok := keysByName[strings.ToLower(string(text))]
+	if !ok {
+		return fmt.Errorf("unknown key name %q", text)
+	}
+	*key = k
+	return nil
+}
+
+// String returns the name of the mouse button, e.g., "MouseLeft" or "Mouse4".
+func (button MouseButton) String() string {
+	if name, ok := mouseButtonNames[button]; ok {
+		return name
+	}
+	return fmt.Sprintf("MouseButton(%d)", int(button))
+}
+
+// MarshalText returns the name of the mouse button.
+func (button MouseButton) MarshalText() ([]byte, error) {
+	name, ok := mouseButtonNames[button]
+	if !ok {
+		return nil, fmt.Errorf("can't marshal unknown mouse button %d", int(button))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText sets the mouse button from its name. Case is ignored.
+func (button *MouseButton) UnmarshalText(text []byte) error {
+	b, ok := mouseButtonsByName[strings.ToLower(string(text))]
+	if !ok {
+		return fmt.Errorf("unknown mouse button name %q", text)
+	}
+	*button = b
+	return nil
+}
+
+// modifierNames are the names of modifier keys, in the order they're written in.
+var modifierNames = []struct {
+	mod  ModifierKey
+	name string
+}{
+	{ModControl, "Ctrl"},
+	{ModShift, "Shift"},
+	{ModAlt, "Alt"},
+	{ModSuper, "Super"},
+	{ModCapsLock, "CapsLock"},
+	{ModNumLock, "NumLock"},
+}
+
+// modifiersByName maps lowercase names of modifier keys, including common aliases, to modifier keys.
+var modifiersByName = map[string]ModifierKey{
+	"ctrl":     ModControl,
+	"control":  ModControl,
+	"shift":    ModShift,
+	"alt":      ModAlt,
+	"option":   ModAlt,
+	"super":    ModSuper,
+	"cmd":      ModSuper,
+	"command":  ModSuper,
+	"meta":     ModSuper,
+	"win":      ModSuper,
+	"capslock": ModCapsLock,
+	"numlock":  ModNumLock,
+}
+
+// String returns the names of the modifier keys joined by "+", e.g., "Ctrl+Shift".
+func (mods ModifierKey) String() string {
+	var names []string
+	for _, m := range modifierNames {
+		if mods&m.mod != 0 {
+			names = append(names, m.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
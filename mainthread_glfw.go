@@ -0,0 +1,91 @@
+// +build !js
+
+package glfw
+
+import (
+	"sync"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// mainFuncs queues functions passed to Do, to be run on the main thread by Main.
+var mainFuncs = make(chan func(), 64)
+
+var (
+	// initializedMu guards writes to initialized, and calls to glfw.PostEmptyEvent from other goroutines.
+	initializedMu sync.Mutex
+	initialized   bool // initialized is true between successful calls to Init and Terminate.
+)
+
+// Main runs f in a new goroutine, and runs the event loop on the main thread until f returns.
+// It must be called from the main goroutine, typically as the only statement in func main.
+//
+// While Main is running, events are processed as they arrive, so callbacks are called
+// without the need to call PollEvents or WaitEvents. All other calls into this package,
+// including Init and CreateWindow, must be made from the main thread by passing them to Do.
+//
+// Events delivered by Main don't end a frame of InputState and InputMap, since Main also wakes
+// up for functions passed to Do. Frames end when the application calls PollEvents or WaitEvents
+// through Do, e.g., once per rendered frame.
+func Main(f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer wakeMain()
+		defer close(done)
+		f()
+	}()
+
+	for {
+		// Run queued functions first, since they may be waiting for the main thread.
+		select {
+		case fn := <-mainFuncs:
+			fn()
+			continue
+		case <-done:
+			return
+		default:
+		}
+
+		if initialized {
+			waitEvents()
+			continue
+		}
+
+		// Without an initialized library, there are no events to wait for.
+		select {
+		case fn := <-mainFuncs:
+			fn()
+		case <-done:
+			return
+		}
+	}
+}
+
+// Do runs f on the main thread, and returns after f returns. It can be called from any goroutine
+// while Main is running, but not from the main thread itself, i.e., not from within a callback
+// or a function passed to Do.
+func Do(f func()) {
+	done := make(chan struct{})
+	mainFuncs <- func() {
+		defer close(done)
+		f()
+	}
+	wakeMain()
+	<-done
+}
+
+// waitEvents waits for events and delivers them, like WaitEvents, but without ending a frame.
+func waitEvents() {
+	defer recoverError()
+	glfw.WaitEvents()
+}
+
+// wakeMain makes Main stop waiting for events, so that it can run queued functions
+// or notice that it is done.
+func wakeMain() {
+	initializedMu.Lock()
+	defer initializedMu.Unlock()
+	if initialized {
+		glfw.PostEmptyEvent()
+	}
+}
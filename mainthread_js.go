@@ -0,0 +1,15 @@
+// +build js
+
+package glfw
+
+// Main calls f. It exists so that programs using Main and Do work unmodified in the browser,
+// where there is only one thread and the browser runs the event loop.
+func Main(f func()) {
+	f()
+}
+
+// Do calls f. It exists so that programs using Main and Do work unmodified in the browser,
+// where any goroutine may call into this package.
+func Do(f func()) {
+	f()
+}
@@ -0,0 +1,101 @@
+package glfw
+
+// PointerType is the type of device behind a pointer.
+type PointerType int
+
+const (
+	PointerMouse PointerType = iota
+	PointerPen
+	PointerTouch
+)
+
+// PointerEvent describes a change of a pointer, such as a mouse, pen or touch point.
+// Values that the device doesn't report have the defaults documented below.
+type PointerEvent struct {
+	ID    int         // Identifies the pointer for as long as it's active.
+	Type  PointerType // Type of device.
+	Phase TouchPhase  // Began when the pointer starts pressing, moved, then ended or cancelled when it stops.
+
+	X, Y float64 // Position in screen coordinates relative to the window, like those of CursorPosCallback.
+
+	Pressure           float64 // Normalized pressure, in [0, 1]. 0.5 while a button is pressed if pressure isn't reported, 0 otherwise.
+	TangentialPressure float64 // Normalized tangential (barrel) pressure, in [-1, 1]. Defaults to 0.
+	TiltX, TiltY       float64 // Tilt relative to the surface, in degrees, in [-90, 90]. Defaults to 0.
+	Twist              float64 // Clockwise rotation around the major axis, in degrees, in [0, 359]. Defaults to 0.
+	Width, Height      float64 // Size of the contact geometry, in screen coordinates. Defaults to 1.
+}
+
+// PointerCallback is called when a pointer changes. In the browser, it's called for every sample
+// reported by the device, which may be more frequent than the cursor position callback.
+//
+// On desktop, GLFW doesn't report pen or touch data, so the mouse is reported as a pointer
+// with pressure 0.5 while a button is pressed.
+type PointerCallback func(w *Window, event PointerEvent)
+
+func (w *Window) SetPointerCallback(cbfun PointerCallback) (previous PointerCallback) {
+	previous = w.pointerCallback
+	w.pointerCallback = cbfun
+	return previous
+}
+
+func (w *Window) emitPointer(event PointerEvent) {
+	for _, l := range w.listeners {
+		if l.pointerCallback != nil {
+			l.pointerCallback(w, event)
+		}
+	}
+	if w.pointerCallback != nil {
+		w.pointerCallback(w, event)
+	}
+}
+
+// mousePointer reports the mouse as a pointer, for backends that don't report pointers.
+type mousePointer struct {
+	buttons int // Bit set of the pressed mouse buttons.
+	pos     [2]float64
+}
+
+// mousePointerID is the pointer id of the mouse, as in the browser.
+const mousePointerID = 1
+
+func (m *mousePointer) event(phase TouchPhase) PointerEvent {
+	e := PointerEvent{
+		ID:     mousePointerID,
+		Type:   PointerMouse,
+		Phase:  phase,
+		X:      m.pos[0],
+		Y:      m.pos[1],
+		Width:  1,
+		Height: 1,
+	}
+	if m.buttons != 0 {
+		e.Pressure = 0.5
+	}
+	return e
+}
+
+// move returns the pointer event for the cursor moving to xpos, ypos.
+func (m *mousePointer) move(xpos, ypos float64) PointerEvent {
+	m.pos = [2]float64{xpos, ypos}
+	return m.event(TouchMoved)
+}
+
+// button returns the pointer event for a mouse button changing, if any. The pointer begins
+// when the first button is pressed, and ends when the last one is released.
+func (m *mousePointer) button(button MouseButton, action Action) (PointerEvent, bool) {
+	pressed := m.buttons != 0
+	switch action {
+	case Press:
+		m.buttons |= 1 << uint(button)
+	case Release:
+		m.buttons &^= 1 << uint(button)
+	}
+	switch {
+	case !pressed && m.buttons != 0:
+		return m.event(TouchBegan), true
+	case pressed && m.buttons == 0:
+		return m.event(TouchEnded), true
+	default:
+		return PointerEvent{}, false
+	}
+}
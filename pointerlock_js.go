@@ -0,0 +1,129 @@
+// +build js
+
+package glfw
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+)
+
+// unadjustedMovementUnsupported is set to true once the browser rejects the unadjustedMovement
+// option of requestPointerLock, which is used for RawMouseMotion.
+var unadjustedMovementUnsupported bool
+
+// pointerLock holds the state of the Pointer Lock API for a window, which disables the cursor.
+type pointerLock struct {
+	want      bool // want is true while CursorDisabled is set, even if the browser hasn't locked the pointer yet.
+	request   bool // request is set to true when the lock should be requested in the next user input handler.
+	fromInput bool // fromInput is true if the last request was made from a user input handler.
+	promised  bool // promised is true if the browser reports failures of the last request with a promise, rather than only an event.
+}
+
+// initPointerLock keeps track of whether the pointer is locked, which also changes when the user
+// releases the lock, e.g., by pressing Esc, or when the browser denies it.
+func (w *Window) initPointerLock() {
+	if w.canvas.Underlying().Get("requestPointerLock") == js.Undefined ||
+		document.Underlying().Get("exitPointerLock") == js.Undefined {
+
+		w.missing.pointerLock = true
+		return
+	}
+
+	document.AddEventListener("pointerlockchange", false, func(dom.Event) {
+		locked := document.Underlying().Get("pointerLockElement") == w.canvas.Underlying()
+		switch {
+		case locked && !w.pointerLock.want:
+			// The cursor mode was changed while the request was pending.
+			document.Underlying().Call("exitPointerLock")
+		case locked:
+			w.pointerLock.request = false
+			w.setCursorMode(CursorDisabled)
+		case w.cursorMode == CursorDisabled:
+			// The user released the lock. It's requested again at the next user input, unless
+			// the cursor mode is changed in the meantime, e.g., from the cursor mode callback.
+			w.pointerLock.request = true
+			w.setCursorMode(CursorNormal)
+		}
+	})
+	document.AddEventListener("pointerlockerror", false, func(dom.Event) {
+		if !w.pointerLock.promised {
+			w.pointerLockFailed("")
+		}
+	})
+}
+
+// lockPointer requests pointer lock. fromInput reports whether it's called from a user input handler.
+func (w *Window) lockPointer(fromInput bool) {
+	w.pointerLock.fromInput = fromInput
+
+	var args []interface{}
+	unadjusted := w.rawMouseMotion && !unadjustedMovementUnsupported
+	if unadjusted {
+		args = append(args, js.M{"unadjustedMovement": true})
+	}
+	promise := w.canvas.Underlying().Call("requestPointerLock", args...)
+
+	w.pointerLock.promised = promise != nil && promise != js.Undefined && promise.Get("catch") != js.Undefined
+	if !w.pointerLock.promised {
+		return
+	}
+	promise.Call("catch", func(err *js.Object) {
+		if unadjusted && err.Get("name").String() == "NotSupportedError" {
+			unadjustedMovementUnsupported = true
+			w.lockPointer(fromInput)
+			return
+		}
+		w.pointerLockFailed(err.Get("message").String())
+	})
+}
+
+// pointerLockFailed handles a denied pointer lock request by requesting it again in the next
+// user input handler, since browsers may only grant it then. Requests that were made from one
+// are reported to the error callback.
+func (w *Window) pointerLockFailed(reason string) {
+	if !w.pointerLock.want {
+		return
+	}
+	w.pointerLock.request = true
+	if !w.pointerLock.fromInput {
+		return
+	}
+	desc := "pointer lock request denied"
+	if reason != "" {
+		desc += ": " + reason
+	}
+	go reportError(PlatformError, desc)
+}
+
+// lockPointerIfRequested requests pointer lock if it was scheduled. It is called only from
+// user input handlers, because the Pointer Lock API may fail if called at any other time.
+func (w *Window) lockPointerIfRequested() {
+	if !w.pointerLock.request {
+		return
+	}
+	w.pointerLock.request = false
+	w.lockPointer(true)
+}
+
+// setCursorMode makes mode the cursor mode, and delivers a cursor mode event if it changed.
+func (w *Window) setCursorMode(mode int) {
+	switch mode {
+	case CursorNormal:
+		w.canvas.Style().SetProperty("cursor", "initial", "")
+	case CursorHidden:
+		w.canvas.Style().SetProperty("cursor", "none", "")
+	}
+	if mode == w.cursorMode {
+		return
+	}
+	w.cursorMode = mode
+	w.queueEvent(func() { w.emitCursorMode(mode) })
+}
+
+// RawMouseMotionSupported reports whether raw mouse motion can be enabled with the RawMouseMotion
+// input mode. In the browser, it's reported as supported until a request to lock the pointer
+// with unadjusted movement is rejected, since browsers don't tell beforehand.
+func RawMouseMotionSupported() bool {
+	return !unadjustedMovementUnsupported &&
+		js.Global.Get("HTMLCanvasElement").Get("prototype").Get("requestPointerLock") != js.Undefined
+}
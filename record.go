@@ -0,0 +1,314 @@
+package glfw
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Input recordings are streams of JSON values, one per line. The first is a recordingHeader,
+// and each following one is a recordedEvent. The end of each frame is marked by an event
+// of type "endFrame".
+
+const (
+	recordingFormat = "goxjs/glfw input recording"
+
+	// recordingVersion is incremented whenever recorded values change meaning. Since version 2,
+	// the browser backend identifies keys by position rather than by label, and all keys have
+	// valid values, so older recordings would replay different keys.
+	recordingVersion = 2
+)
+
+type recordingHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Backend string `json:"backend"` // Backend that made the recording, since key values differ between backends.
+}
+
+// recordedEvent is a single event of a recording. Fields that don't apply to the event type are omitted.
+type recordedEvent struct {
+	Time  float64 `json:"t"`     // Seconds since the recording started.
+	Frame int     `json:"frame"` // Index of the frame during which the event occurred.
+	Type  string  `json:"type"`
+
+	Key      int      `json:"key,omitempty"`
+	Scancode int      `json:"scancode,omitempty"`
+	Button   int      `json:"button,omitempty"`
+	Action   int      `json:"action,omitempty"`
+	Mods     int      `json:"mods,omitempty"`
+	Char     rune     `json:"char,omitempty"`
+	X        float64  `json:"x,omitempty"`
+	Y        float64  `json:"y,omitempty"`
+	DX       float64  `json:"dx,omitempty"`
+	DY       float64  `json:"dy,omitempty"`
+	Width    int      `json:"width,omitempty"`
+	Height   int      `json:"height,omitempty"`
+	Value    bool     `json:"value,omitempty"` // Focused, iconified, fullscreen, entered or precise.
+	Names    []string `json:"names,omitempty"`
+	ID       int      `json:"id,omitempty"`
+	Phase    int      `json:"phase,omitempty"`
+	Mode     int      `json:"mode,omitempty"` // Cursor mode.
+
+	// Preedit events.
+	Preedit   string `json:"preedit,omitempty"`
+	Cursor    int    `json:"cursor,omitempty"`
+	Committed string `json:"committed,omitempty"`
+
+	// Pointer events.
+	PointerType        int     `json:"pointerType,omitempty"`
+	Pressure           float64 `json:"pressure,omitempty"`
+	TangentialPressure float64 `json:"tangentialPressure,omitempty"`
+	TiltX              float64 `json:"tiltX,omitempty"`
+	TiltY              float64 `json:"tiltY,omitempty"`
+	Twist              float64 `json:"twist,omitempty"`
+	ContactWidth       float64 `json:"contactWidth,omitempty"`
+	ContactHeight      float64 `json:"contactHeight,omitempty"`
+}
+
+// Recorder records the events delivered to a window, so that they can be replayed later by a Player.
+// It doesn't interfere with the callbacks set on the window. Events made up from other events, such
+// as the click of a long press, aren't recorded, since replaying the others makes them up again.
+type Recorder struct {
+	w        *Window
+	enc      *json.Encoder
+	start    time.Time
+	frame    int
+	listener *callbacks
+	err      error
+	stopped  bool // stopped is true after Stop, so that frame ends queued before it aren't written.
+}
+
+// NewRecorder starts recording the events of w to dst.
+// EndFrame must be called once per frame, so that the events can be replayed frame by frame.
+func NewRecorder(w *Window, dst io.Writer) (*Recorder, error) {
+	r := &Recorder{
+		w:     w,
+		enc:   json.NewEncoder(dst),
+		start: time.Now(),
+	}
+	err := r.enc.Encode(recordingHeader{Format: recordingFormat, Version: recordingVersion, Backend: backend})
+	if err != nil {
+		return nil, err
+	}
+
+	r.listener = &callbacks{
+		posCallback: func(_ *Window, xpos int, ypos int) {
+			r.record(recordedEvent{Type: "pos", X: float64(xpos), Y: float64(ypos)})
+		},
+		sizeCallback: func(_ *Window, width int, height int) {
+			r.record(recordedEvent{Type: "size", Width: width, Height: height})
+		},
+		framebufferSizeCallback: func(_ *Window, width int, height int) {
+			r.record(recordedEvent{Type: "framebufferSize", Width: width, Height: height})
+		},
+		closeCallback: func(_ *Window) {
+			r.record(recordedEvent{Type: "close"})
+		},
+		refreshCallback: func(_ *Window) {
+			r.record(recordedEvent{Type: "refresh"})
+		},
+		focusCallback: func(_ *Window, focused bool) {
+			r.record(recordedEvent{Type: "focus", Value: focused})
+		},
+		iconifyCallback: func(_ *Window, iconified bool) {
+			r.record(recordedEvent{Type: "iconify", Value: iconified})
+		},
+		fullscreenCallback: func(_ *Window, fullscreen bool) {
+			r.record(recordedEvent{Type: "fullscreen", Value: fullscreen})
+		},
+		cursorPosCallback: func(_ *Window, xpos float64, ypos float64) {
+			r.record(recordedEvent{Type: "cursorPos", X: xpos, Y: ypos})
+		},
+		mouseMovementCallback: func(_ *Window, xpos float64, ypos float64, xdelta float64, ydelta float64) {
+			r.record(recordedEvent{Type: "mouseMovement", X: xpos, Y: ypos, DX: xdelta, DY: ydelta})
+		},
+		cursorEnterCallback: func(_ *Window, entered bool) {
+			r.record(recordedEvent{Type: "cursorEnter", Value: entered})
+		},
+		cursorModeCallback: func(_ *Window, mode int) {
+			r.record(recordedEvent{Type: "cursorMode", Mode: mode})
+		},
+		mouseButtonCallback: func(_ *Window, button MouseButton, action Action, mods ModifierKey) {
+			r.record(recordedEvent{Type: "mouseButton", Button: int(button), Action: int(action), Mods: int(mods)})
+		},
+		scrollCallback: func(_ *Window, xoff float64, yoff float64) {
+			r.record(recordedEvent{Type: "scroll", X: xoff, Y: yoff})
+		},
+		scrollModsCallback: func(_ *Window, xoff float64, yoff float64, mods ModifierKey) {
+			r.record(recordedEvent{Type: "scrollMods", X: xoff, Y: yoff, Mods: int(mods)})
+		},
+		preciseScrollCallback: func(_ *Window, xpixels float64, ypixels float64, precise bool) {
+			r.record(recordedEvent{Type: "preciseScroll", X: xpixels, Y: ypixels, Value: precise})
+		},
+		keyCallback: func(_ *Window, key Key, scancode int, action Action, mods ModifierKey) {
+			r.record(recordedEvent{Type: "key", Key: int(key), Scancode: scancode, Action: int(action), Mods: int(mods)})
+		},
+		charCallback: func(_ *Window, char rune) {
+			r.record(recordedEvent{Type: "char", Char: char})
+		},
+		charModsCallback: func(_ *Window, char rune, mods ModifierKey) {
+			r.record(recordedEvent{Type: "charMods", Char: char, Mods: int(mods)})
+		},
+		preeditCallback: func(_ *Window, preedit string, cursor int, committed string) {
+			r.record(recordedEvent{Type: "preedit", Preedit: preedit, Cursor: cursor, Committed: committed})
+		},
+		dropCallback: func(_ *Window, names []string) {
+			r.record(recordedEvent{Type: "drop", Names: names})
+		},
+		touchCallback: func(_ *Window, id int, phase TouchPhase, x float64, y float64) {
+			r.record(recordedEvent{Type: "touch", ID: id, Phase: int(phase), X: x, Y: y})
+		},
+		pointerCallback: func(_ *Window, e PointerEvent) {
+			r.record(recordedEvent{
+				Type: "pointer", ID: e.ID, PointerType: int(e.Type), Phase: int(e.Phase), X: e.X, Y: e.Y,
+				Pressure: e.Pressure, TangentialPressure: e.TangentialPressure,
+				TiltX: e.TiltX, TiltY: e.TiltY, Twist: e.Twist,
+				ContactWidth: e.Width, ContactHeight: e.Height,
+			})
+		},
+	}
+	w.addListener(r.listener)
+
+	return r, nil
+}
+
+func (r *Recorder) record(e recordedEvent) {
+	if r.err != nil || r.stopped || r.w.synthetic {
+		return
+	}
+	e.Time = time.Since(r.start).Seconds()
+	e.Frame = r.frame
+	r.err = r.enc.Encode(e)
+}
+
+// EndFrame marks the end of the current frame. Events that occur afterwards belong to the next frame.
+func (r *Recorder) EndFrame() {
+	// The browser delivers events after its event handlers return, so the end of the frame
+	// is queued behind the events that occurred so far, rather than recorded right away.
+	r.w.queueEvent(func() {
+		r.record(recordedEvent{Type: "endFrame"})
+		r.frame++
+	})
+}
+
+// Stop stops recording. It returns the first error that occurred while writing the recording, if any.
+func (r *Recorder) Stop() error {
+	r.stopped = true
+	r.w.removeListener(r.listener)
+	return r.err
+}
+
+// Player replays a recording made by a Recorder, delivering its events to the callbacks of a window
+// as if they had just occurred. State that is polled, such as that returned by GetKey and GetCursorPos,
+// is not affected.
+type Player struct {
+	w   *Window
+	dec *json.Decoder
+	err error
+}
+
+// NewPlayer prepares to replay the recording read from src to w.
+// The recording must have been made with the same backend.
+func NewPlayer(w *Window, src io.Reader) (*Player, error) {
+	dec := json.NewDecoder(src)
+	var h recordingHeader
+	if err := dec.Decode(&h); err != nil {
+		return nil, err
+	}
+	switch {
+	case h.Format != recordingFormat:
+		return nil, fmt.Errorf("not an input recording")
+	case h.Version != recordingVersion:
+		return nil, fmt.Errorf("unsupported input recording version %v", h.Version)
+	case h.Backend != backend:
+		return nil, fmt.Errorf("input recording was made with %s backend, can't replay it with %s backend", h.Backend, backend)
+	}
+	return &Player{w: w, dec: dec}, nil
+}
+
+// PlayFrame delivers the events of the next recorded frame. It returns false when the end
+// of the recording is reached, or an error occurs; use Err to tell the two apart.
+func (p *Player) PlayFrame() bool {
+	if p.err != nil {
+		return false
+	}
+	played := false
+	for {
+		var e recordedEvent
+		if err := p.dec.Decode(&e); err == io.EOF {
+			// The recording may have been stopped in the middle of a frame.
+			return played
+		} else if err != nil {
+			p.err = err
+			return false
+		}
+		if e.Type == "endFrame" {
+			return true
+		}
+		p.play(e)
+		played = true
+	}
+}
+
+// Err returns the error that stopped playback, if any.
+func (p *Player) Err() error {
+	return p.err
+}
+
+func (p *Player) play(e recordedEvent) {
+	w := p.w
+	switch e.Type {
+	case "pos":
+		w.emitPos(int(e.X), int(e.Y))
+	case "size":
+		w.emitSize(e.Width, e.Height)
+	case "framebufferSize":
+		w.emitFramebufferSize(e.Width, e.Height)
+	case "close":
+		w.emitClose()
+	case "refresh":
+		w.emitRefresh()
+	case "focus":
+		w.emitFocus(e.Value)
+	case "iconify":
+		w.emitIconify(e.Value)
+	case "fullscreen":
+		w.emitFullscreen(e.Value)
+	case "cursorPos":
+		w.emitCursorPos(e.X, e.Y)
+	case "mouseMovement":
+		w.emitMouseMovement(e.X, e.Y, e.DX, e.DY)
+	case "cursorEnter":
+		w.emitCursorEnter(e.Value)
+	case "cursorMode":
+		w.emitCursorMode(e.Mode)
+	case "mouseButton":
+		w.emitMouseButton(MouseButton(e.Button), Action(e.Action), ModifierKey(e.Mods))
+	case "scroll":
+		w.emitScroll(e.X, e.Y)
+	case "scrollMods":
+		w.emitScrollMods(e.X, e.Y, ModifierKey(e.Mods))
+	case "preciseScroll":
+		w.emitPreciseScroll(e.X, e.Y, e.Value)
+	case "key":
+		w.emitKey(Key(e.Key), e.Scancode, Action(e.Action), ModifierKey(e.Mods))
+	case "char":
+		w.emitChar(e.Char)
+	case "charMods":
+		w.emitCharMods(e.Char, ModifierKey(e.Mods))
+	case "preedit":
+		w.emitPreedit(e.Preedit, e.Cursor, e.Committed)
+	case "drop":
+		w.emitDrop(e.Names)
+	case "touch":
+		w.emitTouch(e.ID, TouchPhase(e.Phase), e.X, e.Y)
+	case "pointer":
+		w.emitPointer(PointerEvent{
+			ID: e.ID, Type: PointerType(e.PointerType), Phase: TouchPhase(e.Phase), X: e.X, Y: e.Y,
+			Pressure: e.Pressure, TangentialPressure: e.TangentialPressure,
+			TiltX: e.TiltX, TiltY: e.TiltY, Twist: e.Twist,
+			Width: e.ContactWidth, Height: e.ContactHeight,
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package glfw
+
+// SafeWindow wraps a Window so that its methods can be called from any goroutine while Main is running.
+// Each method calls the corresponding Window method on the main thread via Do.
+type SafeWindow struct {
+	w *Window
+}
+
+// Safe returns a SafeWindow that wraps w.
+func (w *Window) Safe() *SafeWindow {
+	return &SafeWindow{w: w}
+}
+
+// Window returns the wrapped Window. Its methods must only be called from the main thread.
+func (s *SafeWindow) Window() *Window {
+	return s.w
+}
+
+func (s *SafeWindow) MakeContextCurrent() {
+	Do(s.w.MakeContextCurrent)
+}
+
+func (s *SafeWindow) SwapBuffers() {
+	Do(s.w.SwapBuffers)
+}
+
+func (s *SafeWindow) Destroy() {
+	Do(s.w.Destroy)
+}
+
+func (s *SafeWindow) ShouldClose() (value bool) {
+	Do(func() { value = s.w.ShouldClose() })
+	return value
+}
+
+func (s *SafeWindow) SetShouldClose(value bool) {
+	Do(func() { s.w.SetShouldClose(value) })
+}
+
+func (s *SafeWindow) SetTitle(title string) {
+	Do(func() { s.w.SetTitle(title) })
+}
+
+func (s *SafeWindow) GetPos() (x, y int) {
+	Do(func() { x, y = s.w.GetPos() })
+	return x, y
+}
+
+func (s *SafeWindow) SetPos(xpos, ypos int) {
+	Do(func() { s.w.SetPos(xpos, ypos) })
+}
+
+func (s *SafeWindow) GetSize() (width, height int) {
+	Do(func() { width, height = s.w.GetSize() })
+	return width, height
+}
+
+func (s *SafeWindow) SetSize(width, height int) {
+	Do(func() { s.w.SetSize(width, height) })
+}
+
+func (s *SafeWindow) GetFramebufferSize() (width, height int) {
+	Do(func() { width, height = s.w.GetFramebufferSize() })
+	return width, height
+}
+
+func (s *SafeWindow) Show() {
+	Do(s.w.Show)
+}
+
+func (s *SafeWindow) Hide() {
+	Do(s.w.Hide)
+}
+
+func (s *SafeWindow) GetCursorPos() (x, y float64) {
+	Do(func() { x, y = s.w.GetCursorPos() })
+	return x, y
+}
+
+func (s *SafeWindow) GetKey(key Key) (action Action) {
+	Do(func() { action = s.w.GetKey(key) })
+	return action
+}
+
+func (s *SafeWindow) GetMouseButton(button MouseButton) (action Action) {
+	Do(func() { action = s.w.GetMouseButton(button) })
+	return action
+}
+
+func (s *SafeWindow) GetInputMode(mode InputMode) (value int) {
+	Do(func() { value = s.w.GetInputMode(mode) })
+	return value
+}
+
+func (s *SafeWindow) SetInputMode(mode InputMode, value int) {
+	Do(func() { s.w.SetInputMode(mode, value) })
+}
+
+func (s *SafeWindow) SetClipboardString(str string) {
+	Do(func() { s.w.SetClipboardString(str) })
+}
+
+func (s *SafeWindow) GetClipboardString() (str string, err error) {
+	Do(func() { str, err = s.w.GetClipboardString() })
+	return str, err
+}
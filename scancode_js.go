@@ -0,0 +1,230 @@
+// +build js
+
+package glfw
+
+import (
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Scancodes are derived from KeyboardEvent.code, which identifies the physical key
+// regardless of the keyboard layout. They're the USB HID usage IDs of the keys,
+// which the values of KeyboardEvent.code are named after, so they're stable across
+// browsers and platforms.
+
+// keyCodes lists the keys with their KeyboardEvent.code, scancode, and the label they
+// have on a US keyboard. Only printable keys have labels.
+var keyCodes = []struct {
+	key      Key
+	code     string
+	scancode int
+	label    string
+}{
+	{KeySpace, "Space", 0x2C, ""},
+	{KeyApostrophe, "Quote", 0x34, "'"},
+	{KeyComma, "Comma", 0x36, ","},
+	{KeyMinus, "Minus", 0x2D, "-"},
+	{KeyPeriod, "Period", 0x37, "."},
+	{KeySlash, "Slash", 0x38, "/"},
+	{Key0, "Digit0", 0x27, "0"},
+	{Key1, "Digit1", 0x1E, "1"},
+	{Key2, "Digit2", 0x1F, "2"},
+	{Key3, "Digit3", 0x20, "3"},
+	{Key4, "Digit4", 0x21, "4"},
+	{Key5, "Digit5", 0x22, "5"},
+	{Key6, "Digit6", 0x23, "6"},
+	{Key7, "Digit7", 0x24, "7"},
+	{Key8, "Digit8", 0x25, "8"},
+	{Key9, "Digit9", 0x26, "9"},
+	{KeySemicolon, "Semicolon", 0x33, ";"},
+	{KeyEqual, "Equal", 0x2E, "="},
+	{KeyA, "KeyA", 0x04, "a"},
+	{KeyB, "KeyB", 0x05, "b"},
+	{KeyC, "KeyC", 0x06, "c"},
+	{KeyD, "KeyD", 0x07, "d"},
+	{KeyE, "KeyE", 0x08, "e"},
+	{KeyF, "KeyF", 0x09, "f"},
+	{KeyG, "KeyG", 0x0A, "g"},
+	{KeyH, "KeyH", 0x0B, "h"},
+	{KeyI, "KeyI", 0x0C, "i"},
+	{KeyJ, "KeyJ", 0x0D, "j"},
+	{KeyK, "KeyK", 0x0E, "k"},
+	{KeyL, "KeyL", 0x0F, "l"},
+	{KeyM, "KeyM", 0x10, "m"},
+	{KeyN, "KeyN", 0x11, "n"},
+	{KeyO, "KeyO", 0x12, "o"},
+	{KeyP, "KeyP", 0x13, "p"},
+	{KeyQ, "KeyQ", 0x14, "q"},
+	{KeyR, "KeyR", 0x15, "r"},
+	{KeyS, "KeyS", 0x16, "s"},
+	{KeyT, "KeyT", 0x17, "t"},
+	{KeyU, "KeyU", 0x18, "u"},
+	{KeyV, "KeyV", 0x19, "v"},
+	{KeyW, "KeyW", 0x1A, "w"},
+	{KeyX, "KeyX", 0x1B, "x"},
+	{KeyY, "KeyY", 0x1C, "y"},
+	{KeyZ, "KeyZ", 0x1D, "z"},
+	{KeyLeftBracket, "BracketLeft", 0x2F, "["},
+	{KeyBackslash, "Backslash", 0x31, "\\"},
+	{KeyRightBracket, "BracketRight", 0x30, "]"},
+	{KeyGraveAccent, "Backquote", 0x35, "`"},
+	{KeyWorld1, "IntlBackslash", 0x64, ""},
+	{KeyWorld2, "IntlRo", 0x87, ""},
+	{KeyEscape, "Escape", 0x29, ""},
+	{KeyEnter, "Enter", 0x28, ""},
+	{KeyTab, "Tab", 0x2B, ""},
+	{KeyBackspace, "Backspace", 0x2A, ""},
+	{KeyInsert, "Insert", 0x49, ""},
+	{KeyDelete, "Delete", 0x4C, ""},
+	{KeyRight, "ArrowRight", 0x4F, ""},
+	{KeyLeft, "ArrowLeft", 0x50, ""},
+	{KeyDown, "ArrowDown", 0x51, ""},
+	{KeyUp, "ArrowUp", 0x52, ""},
+	{KeyPageUp, "PageUp", 0x4B, ""},
+	{KeyPageDown, "PageDown", 0x4E, ""},
+	{KeyHome, "Home", 0x4A, ""},
+	{KeyEnd, "End", 0x4D, ""},
+	{KeyCapsLock, "CapsLock", 0x39, ""},
+	{KeyScrollLock, "ScrollLock", 0x47, ""},
+	{KeyNumLock, "NumLock", 0x53, ""},
+	{KeyPrintScreen, "PrintScreen", 0x46, ""},
+	{KeyPause, "Pause", 0x48, ""},
+	{KeyF1, "F1", 0x3A, ""},
+	{KeyF2, "F2", 0x3B, ""},
+	{KeyF3, "F3", 0x3C, ""},
+	{KeyF4, "F4", 0x3D, ""},
+	{KeyF5, "F5", 0x3E, ""},
+	{KeyF6, "F6", 0x3F, ""},
+	{KeyF7, "F7", 0x40, ""},
+	{KeyF8, "F8", 0x41, ""},
+	{KeyF9, "F9", 0x42, ""},
+	{KeyF10, "F10", 0x43, ""},
+	{KeyF11, "F11", 0x44, ""},
+	{KeyF12, "F12", 0x45, ""},
+	{KeyF13, "F13", 0x68, ""},
+	{KeyF14, "F14", 0x69, ""},
+	{KeyF15, "F15", 0x6A, ""},
+	{KeyF16, "F16", 0x6B, ""},
+	{KeyF17, "F17", 0x6C, ""},
+	{KeyF18, "F18", 0x6D, ""},
+	{KeyF19, "F19", 0x6E, ""},
+	{KeyF20, "F20", 0x6F, ""},
+	{KeyF21, "F21", 0x70, ""},
+	{KeyF22, "F22", 0x71, ""},
+	{KeyF23, "F23", 0x72, ""},
+	{KeyF24, "F24", 0x73, ""},
+	{KeyKP0, "Numpad0", 0x62, "0"},
+	{KeyKP1, "Numpad1", 0x59, "1"},
+	{KeyKP2, "Numpad2", 0x5A, "2"},
+	{KeyKP3, "Numpad3", 0x5B, "3"},
+	{KeyKP4, "Numpad4", 0x5C, "4"},
+	{KeyKP5, "Numpad5", 0x5D, "5"},
+	{KeyKP6, "Numpad6", 0x5E, "6"},
+	{KeyKP7, "Numpad7", 0x5F, "7"},
+	{KeyKP8, "Numpad8", 0x60, "8"},
+	{KeyKP9, "Numpad9", 0x61, "9"},
+	{KeyKPDecimal, "NumpadDecimal", 0x63, "."},
+	{KeyKPDivide, "NumpadDivide", 0x54, "/"},
+	{KeyKPMultiply, "NumpadMultiply", 0x55, "*"},
+	{KeyKPSubtract, "NumpadSubtract", 0x56, "-"},
+	{KeyKPAdd, "NumpadAdd", 0x57, "+"},
+	{KeyKPEnter, "NumpadEnter", 0x58, ""},
+	{KeyKPEqual, "NumpadEqual", 0x67, "="},
+	{KeyLeftShift, "ShiftLeft", 0xE1, ""},
+	{KeyLeftControl, "ControlLeft", 0xE0, ""},
+	{KeyLeftAlt, "AltLeft", 0xE2, ""},
+	{KeyLeftSuper, "MetaLeft", 0xE3, ""},
+	{KeyRightShift, "ShiftRight", 0xE5, ""},
+	{KeyRightControl, "ControlRight", 0xE4, ""},
+	{KeyRightAlt, "AltRight", 0xE6, ""},
+	{KeyRightSuper, "MetaRight", 0xE7, ""},
+	{KeyMenu, "ContextMenu", 0x65, ""},
+}
+
+var (
+	codeKeys      = make(map[string]Key, len(keyCodes)) // By KeyboardEvent.code.
+	keyScancodes  = make(map[Key]int, len(keyCodes))
+	scancodeCodes = make(map[int]string, len(keyCodes))
+	usLabels      = make(map[string]string, len(keyCodes)) // By KeyboardEvent.code.
+)
+
+func init() {
+	for _, k := range keyCodes {
+		codeKeys[k.code] = k.key
+		keyScancodes[k.key] = k.scancode
+		scancodeCodes[k.scancode] = k.code
+		if k.label != "" {
+			usLabels[k.code] = k.label
+		}
+	}
+}
+
+// layoutLabels holds the labels of printable keys in the current keyboard layout, by KeyboardEvent.code.
+// It's filled in from navigator.keyboard.getLayoutMap() where available, and from key events.
+var layoutLabels = make(map[string]string)
+
+// loadKeyboardLayout fills in layoutLabels from the keyboard layout map, and keeps it up to date.
+func loadKeyboardLayout() {
+	keyboard := js.Global.Get("navigator").Get("keyboard")
+	if keyboard == js.Undefined || keyboard.Get("getLayoutMap") == js.Undefined {
+		return
+	}
+	load := func() {
+		keyboard.Call("getLayoutMap").Call("then", func(layoutMap *js.Object) {
+			layoutMap.Call("forEach", func(label, code string) {
+				layoutLabels[code] = label
+			})
+		})
+	}
+	load()
+	if keyboard.Get("addEventListener") != js.Undefined {
+		keyboard.Call("addEventListener", "layoutchange", load)
+	}
+}
+
+// learnKeyLabel records the label of a printable key from a key event,
+// as the character it produced, if no modifier keys were held.
+func learnKeyLabel(code, key string, mods ModifierKey) {
+	if mods&(ModControl|ModAlt|ModSuper|ModShift) != 0 {
+		return
+	}
+	if _, printable := usLabels[code]; !printable || len([]rune(key)) != 1 {
+		return
+	}
+	layoutLabels[code] = strings.ToLower(key)
+}
+
+// GetKeyName returns the label of a printable key in the current keyboard layout,
+// e.g., "z" for KeyW on an AZERTY keyboard. If key is KeyUnknown, the key is identified
+// by scancode instead. It returns "" for keys that aren't printable.
+//
+// Before the layout is known, the labels of a US keyboard are returned.
+func GetKeyName(key Key, scancode int) string {
+	if key != KeyUnknown {
+		var ok bool
+		if scancode, ok = keyScancodes[key]; !ok {
+			return ""
+		}
+	}
+	code, ok := scancodeCodes[scancode]
+	if !ok {
+		return ""
+	}
+	if _, printable := usLabels[code]; !printable {
+		return ""
+	}
+	if label, ok := layoutLabels[code]; ok {
+		return label
+	}
+	return usLabels[code]
+}
+
+// GetKeyScancode returns the scancode of key, or -1 if it has none.
+func GetKeyScancode(key Key) int {
+	scancode, ok := keyScancodes[key]
+	if !ok {
+		return -1
+	}
+	return scancode
+}
@@ -0,0 +1,36 @@
+package glfw
+
+// Scroll offsets are normalized so that one notch of a regular mouse wheel scrolls by 1.0,
+// as on desktop GLFW. The browser reports deltas in pixels, lines or pages; they're converted
+// using the amounts a notch typically scrolls by.
+const (
+	scrollPixelsPerUnit = 100 // Pixels per notch, as reported by most browsers.
+	scrollLinesPerUnit  = 3   // Lines per notch, as reported by Firefox.
+	scrollPagesPerUnit  = 1   // Pages per notch, when the system scrolls a page at a time.
+)
+
+// PreciseScrollCallback is called with the scroll offsets in pixels, with the same signs as
+// those of ScrollCallback, before they're normalized. precise is true if the offsets come
+// from a device that scrolls smoothly, such as a trackpad, rather than in notches.
+//
+// Neither backend is told the type of device, so precise is a guess: in the browser,
+// it's based on the reported delta mode and values; on desktop, on whether the offsets
+// are fractional. Offsets not reported in pixels are converted at 100 pixels per notch.
+type PreciseScrollCallback func(w *Window, xpixels float64, ypixels float64, precise bool)
+
+func (w *Window) SetPreciseScrollCallback(cbfun PreciseScrollCallback) (previous PreciseScrollCallback) {
+	previous = w.preciseScrollCallback
+	w.preciseScrollCallback = cbfun
+	return previous
+}
+
+func (w *Window) emitPreciseScroll(xpixels float64, ypixels float64, precise bool) {
+	for _, l := range w.listeners {
+		if l.preciseScrollCallback != nil {
+			l.preciseScrollCallback(w, xpixels, ypixels, precise)
+		}
+	}
+	if w.preciseScrollCallback != nil {
+		w.preciseScrollCallback(w, xpixels, ypixels, precise)
+	}
+}
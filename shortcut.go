@@ -0,0 +1,209 @@
+package glfw
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Chord is a key pressed with modifier keys held, such as Ctrl+S.
+type Chord struct {
+	Key  Key
+	Mods ModifierKey // Modifier keys that must be held, and no others. Lock key modifiers are ignored.
+}
+
+// String returns the chord as ParseShortcut reads it, with the names of modifier keys
+// on the platform: on macOS, Super is written as "Cmd", Alt as "Option", and Ctrl as "MacCtrl".
+func (c Chord) String() string {
+	var names []string
+	for _, m := range modifierNames {
+		if c.Mods&m.mod != 0 && m.mod&(ModCapsLock|ModNumLock) == 0 {
+			names = append(names, chordModifierName(m.mod, m.name))
+		}
+	}
+	return strings.Join(append(names, c.Key.String()), "+")
+}
+
+// chordModifierName returns the name of mod in chords, given its usual name.
+func chordModifierName(mod ModifierKey, name string) string {
+	if PrimaryModifier() != ModSuper {
+		return name
+	}
+	switch mod {
+	case ModSuper:
+		return "Cmd"
+	case ModAlt:
+		return "Option"
+	case ModControl:
+		return "MacCtrl" // "Ctrl" stands for Cmd.
+	default:
+		return name
+	}
+}
+
+// Shortcut is a sequence of one or more chords, such as Ctrl+K Ctrl+C.
+type Shortcut []Chord
+
+// ParseShortcut parses a shortcut, written as chords separated by spaces. Each chord is written
+// as the names of modifier keys and of a key, joined by "+", like a Binding. Case is ignored.
+//
+// "Ctrl" (or "Control") stands for the primary modifier of the platform, as returned by
+// PrimaryModifier, as do "Primary" and "CmdOrCtrl". So "Ctrl+Shift+S" is Cmd+Shift+S on macOS
+// and Ctrl+Shift+S elsewhere. "MacCtrl" stands for the Ctrl key itself on all platforms.
+func ParseShortcut(s string) (Shortcut, error) {
+	var shortcut Shortcut
+	for _, text := range strings.Fields(s) {
+		c, err := parseChord(text)
+		if err != nil {
+			return nil, fmt.Errorf("shortcut %q: %v", s, err)
+		}
+		shortcut = append(shortcut, c)
+	}
+	if len(shortcut) == 0 {
+		return nil, fmt.Errorf("empty shortcut")
+	}
+	return shortcut, nil
+}
+
+func parseChord(text string) (Chord, error) {
+	parts := strings.Split(text, "+")
+	var c Chord
+	for _, part := range parts[:len(parts)-1] {
+		switch name := strings.ToLower(part); name {
+		case "primary", "cmdorctrl", "ctrl", "control":
+			c.Mods |= PrimaryModifier()
+		case "macctrl":
+			c.Mods |= ModControl
+		default:
+			mod, ok := modifiersByName[name]
+			if !ok || mod == ModCapsLock || mod == ModNumLock {
+				return Chord{}, fmt.Errorf("unknown modifier key %q", part)
+			}
+			c.Mods |= mod
+		}
+	}
+	key, ok := keysByName[strings.ToLower(parts[len(parts)-1])]
+	if !ok {
+		return Chord{}, fmt.Errorf("unknown key %q", parts[len(parts)-1])
+	}
+	c.Key = key
+	return c, nil
+}
+
+func (s Shortcut) String() string {
+	chords := make([]string, len(s))
+	for i, c := range s {
+		chords[i] = c.String()
+	}
+	return strings.Join(chords, " ")
+}
+
+// hasPrefix reports whether prefix is a proper prefix of s.
+func (s Shortcut) hasPrefix(prefix Shortcut) bool {
+	if len(prefix) >= len(s) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Shortcut) equal(other Shortcut) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if s[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ShortcutRegistry dispatches key events to the handlers of matching shortcuts.
+// Its KeyCallback method can be set as the key callback of a window, or HandleKey
+// can be called from one.
+type ShortcutRegistry struct {
+	shortcuts []registeredShortcut
+	pending   Shortcut // Chords typed so far of a shortcut that has more.
+}
+
+type registeredShortcut struct {
+	shortcut Shortcut
+	handler  func(w *Window)
+}
+
+// NewShortcutRegistry returns an empty shortcut registry.
+func NewShortcutRegistry() *ShortcutRegistry {
+	return &ShortcutRegistry{}
+}
+
+// Register parses shortcut, and registers handler to be called when it's typed.
+func (r *ShortcutRegistry) Register(shortcut string, handler func(w *Window)) error {
+	s, err := ParseShortcut(shortcut)
+	if err != nil {
+		return err
+	}
+	r.RegisterShortcut(s, handler)
+	return nil
+}
+
+// RegisterShortcut registers handler to be called when shortcut is typed.
+// If a shortcut is also the beginning of a longer one, the shorter one takes precedence.
+func (r *ShortcutRegistry) RegisterShortcut(shortcut Shortcut, handler func(w *Window)) {
+	r.shortcuts = append(r.shortcuts, registeredShortcut{shortcut: shortcut, handler: handler})
+}
+
+// KeyCallback is a KeyCallback that dispatches key events to the registered shortcuts.
+func (r *ShortcutRegistry) KeyCallback(w *Window, key Key, scancode int, action Action, mods ModifierKey) {
+	r.HandleKey(w, key, action, mods)
+}
+
+// HandleKey dispatches a key event to the registered shortcuts. It reports whether the event
+// was consumed, because it completed a shortcut or continued a sequence of chords.
+func (r *ShortcutRegistry) HandleKey(w *Window, key Key, action Action, mods ModifierKey) bool {
+	if action != Press || isModifierKey(key) {
+		return false
+	}
+	chord := Chord{Key: key, Mods: mods &^ (ModCapsLock | ModNumLock)}
+
+	if len(r.pending) > 0 {
+		pending := append(r.pending, chord)
+		r.pending = nil
+		if r.match(w, pending) {
+			return true
+		}
+		// The sequence was broken; the chord may start another one.
+	}
+	return r.match(w, Shortcut{chord})
+}
+
+// match calls the handler of the shortcut typed so far if it's complete,
+// or keeps it pending if it's the beginning of one.
+func (r *ShortcutRegistry) match(w *Window, typed Shortcut) bool {
+	for _, s := range r.shortcuts {
+		if s.shortcut.equal(typed) {
+			s.handler(w)
+			return true
+		}
+	}
+	for _, s := range r.shortcuts {
+		if s.shortcut.hasPrefix(typed) {
+			r.pending = typed
+			return true
+		}
+	}
+	return false
+}
+
+func isModifierKey(key Key) bool {
+	switch key {
+	case KeyLeftShift, KeyRightShift, KeyLeftControl, KeyRightControl,
+		KeyLeftAlt, KeyRightAlt, KeyLeftSuper, KeyRightSuper:
+		return true
+	default:
+		return false
+	}
+}
@@ -300,6 +300,10 @@ func buttonString(button glfw.MouseButton) string {
 		return "right"
 	case glfw.MouseButtonMiddle:
 		return "middle"
+	case glfw.MouseButton4:
+		return "back"
+	case glfw.MouseButton5:
+		return "forward"
 	default:
 		return fmt.Sprint(button)
 	}
@@ -322,6 +326,12 @@ func modsString(mods glfw.ModifierKey) string {
 	if mods&glfw.ModSuper != 0 {
 		name += " super"
 	}
+	if mods&glfw.ModCapsLock != 0 {
+		name += " capslock-on"
+	}
+	if mods&glfw.ModNumLock != 0 {
+		name += " numlock-on"
+	}
 	return name
 }
 
@@ -329,6 +339,10 @@ func charString(char rune) string {
 	return fmt.Sprintf("%#q", char)
 }
 
+func ErrorCallback(code glfw.ErrorCode, desc string) {
+	fmt.Printf("Error: %v: %s\n", code, desc)
+}
+
 func PosCallback(w *glfw.Window, x int, y int) {
 	fmt.Printf("%08x to %v at %0.3f: Window position: %v %v\n",
 		getCounter(), getWindowId(w), getTime(),
@@ -385,6 +399,17 @@ func MouseButtonCallback(w *glfw.Window, button glfw.MouseButton, action glfw.Ac
 		button, buttonString(button), modsString(mods), actionString(action))
 }
 
+func FullscreenCallback(w *glfw.Window, fullscreen bool) {
+	fullscreenString := map[bool]string{
+		true:  "entered",
+		false: "left",
+	}
+
+	fmt.Printf("%08x to %v at %0.3f: Window %s fullscreen\n",
+		getCounter(), getWindowId(w), getTime(),
+		fullscreenString[fullscreen])
+}
+
 func CursorPosCallback(w *glfw.Window, x float64, y float64) {
 	fmt.Printf("%08x to %v at %0.3f: Cursor position: %f %f\n",
 		getCounter(), getWindowId(w), getTime(),
@@ -402,16 +427,47 @@ func CursorEnterCallback(w *glfw.Window, entered bool) {
 		enteredString[entered])
 }
 
+func CursorModeCallback(w *glfw.Window, mode int) {
+	cursorModeString := map[int]string{
+		glfw.CursorNormal:   "normal",
+		glfw.CursorHidden:   "hidden",
+		glfw.CursorDisabled: "disabled",
+	}
+
+	fmt.Printf("%08x to %v at %0.3f: Cursor mode changed to %s\n",
+		getCounter(), getWindowId(w), getTime(),
+		cursorModeString[mode])
+}
+
 func ScrollCallback(w *glfw.Window, x float64, y float64) {
 	fmt.Printf("%08x to %v at %0.3f: Scroll: %0.3f %0.3f\n",
 		getCounter(), getWindowId(w), getTime(),
 		x, y)
 }
 
-func KeyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-	fmt.Printf("%08x to %v at %0.3f: Key 0x%04x Scancode 0x%04x (%s) (with%s) was %s\n",
+func ScrollModsCallback(w *glfw.Window, x float64, y float64, mods glfw.ModifierKey) {
+	fmt.Printf("%08x to %v at %0.3f: Scroll: %0.3f %0.3f (with%s)\n",
 		getCounter(), getWindowId(w), getTime(),
-		key, scancode, keyString(key), modsString(mods), actionString(action))
+		x, y, modsString(mods))
+}
+
+func PreciseScrollCallback(w *glfw.Window, x float64, y float64, precise bool) {
+	fmt.Printf("%08x to %v at %0.3f: Scroll: %0.3f %0.3f pixels (precise: %v)\n",
+		getCounter(), getWindowId(w), getTime(),
+		x, y, precise)
+}
+
+func KeyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	name := glfw.GetKeyName(key, scancode)
+	if name != "" {
+		fmt.Printf("%08x to %v at %0.3f: Key 0x%04x Scancode 0x%04x (%s) (%s) (with%s) was %s\n",
+			getCounter(), getWindowId(w), getTime(),
+			int(key), scancode, keyString(key), name, modsString(mods), actionString(action))
+	} else {
+		fmt.Printf("%08x to %v at %0.3f: Key 0x%04x Scancode 0x%04x (%s) (with%s) was %s\n",
+			getCounter(), getWindowId(w), getTime(),
+			int(key), scancode, keyString(key), modsString(mods), actionString(action))
+	}
 }
 
 func CharCallback(w *glfw.Window, char rune) {
@@ -426,6 +482,12 @@ func CharModsCallback(w *glfw.Window, char rune, mods glfw.ModifierKey) {
 		char, charString(char), modsString(mods))
 }
 
+func PreeditCallback(w *glfw.Window, preedit string, cursor int, committed string) {
+	fmt.Printf("%08x to %v at %0.3f: Preedit %q with cursor at %v, committed %q\n",
+		getCounter(), getWindowId(w), getTime(),
+		preedit, cursor, committed)
+}
+
 func DropCallback(w *glfw.Window, names []string) {
 	fmt.Printf("%08x to %v at %0.3f: Drop input\n",
 		getCounter(), getWindowId(w), getTime())
@@ -434,7 +496,48 @@ func DropCallback(w *glfw.Window, names []string) {
 	}
 }
 
+var touchPhaseString = map[glfw.TouchPhase]string{
+	glfw.TouchBegan:     "began",
+	glfw.TouchMoved:     "moved",
+	glfw.TouchEnded:     "ended",
+	glfw.TouchCancelled: "cancelled",
+}
+
+func TouchCallback(w *glfw.Window, id int, phase glfw.TouchPhase, x float64, y float64) {
+	fmt.Printf("%08x to %v at %0.3f: Touch %v %s at %0.3f %0.3f\n",
+		getCounter(), getWindowId(w), getTime(),
+		id, touchPhaseString[phase], x, y)
+}
+
+var gestureTypeString = map[glfw.GestureType]string{
+	glfw.GesturePinch:     "pinch",
+	glfw.GesturePan:       "pan",
+	glfw.GestureLongPress: "long press",
+}
+
+func GestureCallback(w *glfw.Window, gesture glfw.Gesture) {
+	fmt.Printf("%08x to %v at %0.3f: Gesture %s at %0.3f %0.3f: scale %0.3f, translation %0.3f %0.3f\n",
+		getCounter(), getWindowId(w), getTime(),
+		gestureTypeString[gesture.Type], gesture.CentroidX, gesture.CentroidY,
+		gesture.Scale, gesture.TranslationX, gesture.TranslationY)
+}
+
+var pointerTypeString = map[glfw.PointerType]string{
+	glfw.PointerMouse: "mouse",
+	glfw.PointerPen:   "pen",
+	glfw.PointerTouch: "touch",
+}
+
+func PointerCallback(w *glfw.Window, e glfw.PointerEvent) {
+	fmt.Printf("%08x to %v at %0.3f: Pointer %v (%s) %s at %0.3f %0.3f: pressure %0.3f, tangential pressure %0.3f, tilt %0.0f %0.0f, twist %0.0f, size %0.3f %0.3f\n",
+		getCounter(), getWindowId(w), getTime(),
+		e.ID, pointerTypeString[e.Type], touchPhaseString[e.Phase], e.X, e.Y,
+		e.Pressure, e.TangentialPressure, e.TiltX, e.TiltY, e.Twist, e.Width, e.Height)
+}
+
 func main() {
+	glfw.SetErrorCallback(ErrorCallback)
+
 	err := glfw.Init(nil)
 	if err != nil {
 		panic(err)
@@ -456,14 +559,24 @@ func main() {
 	window.SetRefreshCallback(RefreshCallback)
 	window.SetFocusCallback(FocusCallback)
 	window.SetIconifyCallback(IconifyCallback)
+	window.SetFullscreenCallback(FullscreenCallback)
 	window.SetMouseButtonCallback(MouseButtonCallback)
 	window.SetCursorPosCallback(CursorPosCallback)
 	window.SetCursorEnterCallback(CursorEnterCallback)
+	window.SetCursorModeCallback(CursorModeCallback)
 	window.SetScrollCallback(ScrollCallback)
+	window.SetScrollModsCallback(ScrollModsCallback)
+	window.SetPreciseScrollCallback(PreciseScrollCallback)
 	window.SetKeyCallback(KeyCallback)
 	window.SetCharCallback(CharCallback)
 	window.SetCharModsCallback(CharModsCallback)
+	window.SetPreeditCallback(PreeditCallback)
 	window.SetDropCallback(DropCallback)
+	window.SetTouchCallback(TouchCallback)
+	window.SetGestureCallback(GestureCallback)
+	window.SetPointerCallback(PointerCallback)
+
+	window.SetInputMode(glfw.LockKeyMods, 1)
 
 	fmt.Println("Main loop starting.")
 
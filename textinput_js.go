@@ -0,0 +1,239 @@
+// +build js
+
+package glfw
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gopherjs/gopherjs/js"
+	"honnef.co/go/js/dom"
+)
+
+// textInput is a hidden textarea that receives the text input of a window. Browsers only let
+// input methods (IMEs) compose text in an editable element, so text is typed into it, and
+// delivered to the char and preedit callbacks. Key events still bubble up to the document.
+type textInput struct {
+	w         *Window
+	textarea  *dom.HTMLTextAreaElement
+	composing bool        // composing is true while an input method is composing text.
+	mods      ModifierKey // Modifier keys of the last key event, for the char mods callback.
+	keyEvent  bool        // keyEvent is true if the last key event was delivered, so that the edit it makes isn't reported as a key press again.
+
+	active       bool // active is true between StartTextInput and StopTextInput.
+	requestFocus bool // requestFocus is set to true when the soft keyboard should be summoned in the next user input handler.
+}
+
+func newTextInput(w *Window) *textInput {
+	t := &textInput{w: w}
+
+	t.textarea = document.CreateElement("textarea").(*dom.HTMLTextAreaElement)
+	for name, value := range map[string]string{
+		"autocomplete":   "off",
+		"autocorrect":    "off",
+		"autocapitalize": "off",
+		"spellcheck":     "false",
+		"tabindex":       "-1",
+		"aria-hidden":    "true",
+		"inputmode":      "none", // Don't show a soft keyboard unless text input is started.
+	} {
+		t.textarea.SetAttribute(name, value)
+	}
+	style := t.textarea.Style()
+	for name, value := range map[string]string{
+		"position":       "fixed",
+		"left":           "0",
+		"top":            "0",
+		"width":          "1px",
+		"height":         "1px",
+		"padding":        "0",
+		"border":         "0",
+		"outline":        "none",
+		"resize":         "none",
+		"overflow":       "hidden",
+		"opacity":        "0",
+		"pointer-events": "none",
+	} {
+		style.SetProperty(name, value, "")
+	}
+	document.Body().AppendChild(t.textarea)
+	t.clear()
+
+	t.textarea.AddEventListener("compositionstart", false, func(dom.Event) {
+		t.composing = true
+	})
+	t.textarea.AddEventListener("input", false, func(event dom.Event) {
+		if t.composing || event.Underlying().Get("isComposing").Bool() {
+			t.preedit()
+			return
+		}
+		keyEvent := t.keyEvent
+		t.keyEvent = false
+		// Soft keyboards edit the text instead of sending usable key events, so report deletions
+		// and line breaks as key presses. Deleting the sentinel counts, too.
+		inputType := event.Underlying().Get("inputType").String()
+		switch {
+		case keyEvent:
+			// Already reported by the key event that made the edit.
+		case strings.HasPrefix(inputType, "delete") || !strings.HasPrefix(t.textarea.Value, textSentinel):
+			t.pressKey(KeyBackspace)
+		case inputType == "insertLineBreak" || inputType == "insertParagraph":
+			t.pressKey(KeyEnter)
+		}
+		t.commit(t.text())
+	})
+	t.textarea.AddEventListener("compositionend", false, func(dom.Event) {
+		t.composing = false
+		text := t.text()
+		t.w.queueEvent(func() { t.w.emitPreedit("", 0, text) }) // Also clears the preedit if the composition was cancelled.
+		t.commit(text)
+	})
+
+	return t
+}
+
+// textSentinel is the text the textarea holds between inputs, with the cursor after it.
+// Browsers don't report attempts to delete from an empty textarea, but deleting it is reported.
+const textSentinel = "\u200b" // Zero-width space.
+
+// text returns the text typed into the textarea, without the sentinel.
+func (t *textInput) text() string {
+	return strings.TrimPrefix(t.textarea.Value, textSentinel)
+}
+
+// clear empties the textarea, except for the sentinel, and places the cursor after it.
+func (t *textInput) clear() {
+	t.textarea.Value = textSentinel
+	t.textarea.Underlying().Call("setSelectionRange", 1, 1) // The sentinel is one UTF-16 code unit.
+}
+
+// focus focuses the textarea, so that it receives text input.
+func (t *textInput) focus() {
+	if t.focused() {
+		return
+	}
+	t.textarea.Underlying().Call("focus", js.M{"preventScroll": true})
+}
+
+// focused reports whether the textarea has focus.
+func (t *textInput) focused() bool {
+	return document.ActiveElement() != nil && document.ActiveElement().Underlying() == t.textarea.Underlying()
+}
+
+// wantsKey reports whether the default action of a key event must be left to the browser,
+// so that the character it types reaches the textarea.
+func (t *textInput) wantsKey(ke *dom.KeyboardEvent) bool {
+	if !t.focused() {
+		return false
+	}
+	if (ke.CtrlKey || ke.MetaKey) && !isAltGraph(ke) {
+		// Shortcuts don't type text.
+		return false
+	}
+	return utf8.RuneCountInString(ke.Key) == 1
+}
+
+// isAltGraph reports whether AltGr is held, which types characters. On Windows, browsers
+// also report it as Ctrl and Alt, which are treated the same since they type with AltGr, too.
+func isAltGraph(ke *dom.KeyboardEvent) bool {
+	if ke.Get("getModifierState") != js.Undefined && ke.Call("getModifierState", "AltGraph").Bool() {
+		return true
+	}
+	return ke.CtrlKey && ke.AltKey && !ke.MetaKey
+}
+
+// isComposing reports whether a key event is part of a composition by an input method.
+// Some browsers report such events with the legacy keyCode 229 only.
+func isComposing(ke *dom.KeyboardEvent) bool {
+	return ke.Get("isComposing").Bool() || ke.KeyCode == 229
+}
+
+// preedit delivers the text being composed, which is the content of the textarea.
+func (t *textInput) preedit() {
+	// selectionEnd counts UTF-16 code units, so let JavaScript slice the text up to the cursor.
+	beforeCursor := t.textarea.Underlying().Get("value").Call("substring", 0, t.textarea.Underlying().Get("selectionEnd")).String()
+	beforeCursor = strings.TrimPrefix(beforeCursor, textSentinel)
+	text, cursor := t.text(), utf8.RuneCountInString(beforeCursor)
+	t.w.queueEvent(func() { t.w.emitPreedit(text, cursor, "") })
+}
+
+// commit delivers text that was typed or composed, and clears the textarea.
+func (t *textInput) commit(text string) {
+	t.clear()
+	for _, char := range text {
+		if unicode.IsControl(char) {
+			// GLFW doesn't deliver control characters, such as the line breaks of soft keyboards.
+			continue
+		}
+		char, mods := char, t.mods
+		t.w.queueEvent(func() {
+			t.w.emitChar(char)
+			t.w.emitCharMods(char, mods)
+		})
+	}
+}
+
+// pressKey delivers a press and a release of key.
+func (t *textInput) pressKey(key Key) {
+	scancode := GetKeyScancode(key)
+	t.w.queueEvent(func() {
+		t.w.emitKey(key, scancode, Press, 0)
+		t.w.emitKey(key, scancode, Release, 0)
+	})
+}
+
+// start lets the textarea summon the soft keyboard, which browsers only do when an element
+// is focused from a user input handler. So focus is given again in the next one, if needed.
+func (t *textInput) start(x, y, width, height int) {
+	t.active = true
+	t.textarea.SetAttribute("inputmode", "text")
+	t.setCursorRect(x, y, width, height)
+	t.refocus()
+	t.requestFocus = true
+}
+
+// stop hides the soft keyboard. The textarea keeps focus, so that physical keyboards
+// and input methods still work.
+func (t *textInput) stop() {
+	t.active = false
+	t.requestFocus = false
+	t.textarea.SetAttribute("inputmode", "none")
+	t.refocus()
+}
+
+// focusIfRequested summons the soft keyboard if it was requested. It is called only from
+// user input handlers, because browsers ignore the request at any other time.
+func (t *textInput) focusIfRequested() {
+	if !t.requestFocus {
+		return
+	}
+	t.requestFocus = false
+	t.refocus()
+}
+
+// refocus blurs and focuses the textarea, so that a change of its inputmode takes effect.
+func (t *textInput) refocus() {
+	t.textarea.Blur()
+	t.focus()
+}
+
+// setCursorRect moves the textarea over the given rectangle of the window,
+// so that input method windows appear next to it.
+func (t *textInput) setCursorRect(x, y, width, height int) {
+	rect := t.w.canvas.GetBoundingClientRect()
+	style := t.textarea.Style()
+	style.SetProperty("left", fmt.Sprintf("%vpx", rect.Left+float64(x)), "")
+	style.SetProperty("top", fmt.Sprintf("%vpx", rect.Top+float64(y)), "")
+	style.SetProperty("width", fmt.Sprintf("%vpx", max1(width)), "")
+	style.SetProperty("height", fmt.Sprintf("%vpx", max1(height)), "")
+}
+
+// max1 returns v, or 1 if v is smaller, since an empty textarea may not get an input method window.
+func max1(v int) int {
+	if v < 1 {
+		return 1
+	}
+	return v
+}